@@ -0,0 +1,57 @@
+package mysql
+
+import (
+	"database/sql"
+	"sort"
+)
+
+// AutoIncrementUsage is the capacity used by an AUTO_INCREMENT column
+type AutoIncrementUsage struct {
+	Schema     string
+	Table      string
+	Column     string
+	ColumnType string // COLUMN_TYPE, e.g. int(11) unsigned
+	Next       uint64 // AUTO_INCREMENT of the table
+	Max        uint64 // max value of the column type
+	Headroom   uint64 // values left before Max
+	Usage      float64
+}
+
+// CheckAutoIncrement scan the AUTO_INCREMENT columns of the tables in databases, or in all databases
+// except the system ones if none is given, and report those whose usage is at least threshold, the most used first.
+// The capacity is computed from the exact column type and signedness.
+// On 8.0 TABLES.AUTO_INCREMENT is cached for information_schema_stats_expiry seconds.
+func CheckAutoIncrement(db *sql.DB, threshold float64, databases ...string) ([]AutoIncrementUsage, error) {
+	query := `SELECT t.TABLE_SCHEMA, t.TABLE_NAME, c.COLUMN_NAME, c.COLUMN_TYPE, COALESCE(t.AUTO_INCREMENT, 0) 
+			FROM information_schema.COLUMNS c 
+			JOIN information_schema.TABLES t ON t.TABLE_SCHEMA = c.TABLE_SCHEMA AND t.TABLE_NAME = c.TABLE_NAME 
+			WHERE c.EXTRA LIKE '%auto_increment%' AND t.TABLE_TYPE = 'BASE TABLE'`
+	where, args := auditDatabases("t.TABLE_SCHEMA", databases)
+	query += " AND " + where
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var usages []AutoIncrementUsage
+	for rows.Next() {
+		var u AutoIncrementUsage
+		if err = rows.Scan(&u.Schema, &u.Table, &u.Column, &u.ColumnType, &u.Next); err != nil {
+			return nil, err
+		}
+		var ok bool
+		if u.Max, ok = columnMaxValue(u.ColumnType); !ok {
+			continue
+		}
+		u.Headroom, u.Usage = autoIncrementUsage(u.Next, u.Max)
+		if u.Usage >= threshold {
+			usages = append(usages, u)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(usages, func(i, j int) bool { return usages[i].Usage > usages[j].Usage })
+	return usages, nil
+}
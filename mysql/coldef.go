@@ -0,0 +1,185 @@
+package mysql
+
+import (
+	"regexp"
+	"strings"
+)
+
+// numericLiteral matches a decimal or scientific number literal
+var numericLiteral = regexp.MustCompile(`^[+-]?([0-9]+(\.[0-9]*)?|\.[0-9]+)([eE][+-]?[0-9]+)?$`)
+
+// DefaultKind tells how the default value of a column is rendered.
+type DefaultKind uint8
+
+const (
+	DefaultNone       DefaultKind = iota // no DEFAULT clause
+	DefaultLiteral                       // a constant, quoted according to the column type
+	DefaultExpression                    // an expression such as CURRENT_TIMESTAMP, written as is
+	DefaultNull                          // DEFAULT NULL
+)
+
+// ColumnDefault is the default value of a column.
+// The zero value means the column has no DEFAULT clause.
+type ColumnDefault struct {
+	Kind  DefaultKind
+	Value string
+}
+
+// NullDefault renders DEFAULT NULL.
+var NullDefault = ColumnDefault{Kind: DefaultNull}
+
+// LiteralDefault returns a default holding the constant v.
+// v is quoted unless the column is numeric and v is a number, TRUE/FALSE or a bit or hex literal,
+// so an empty string is a valid default.
+func LiteralDefault(v string) ColumnDefault {
+	return ColumnDefault{Kind: DefaultLiteral, Value: v}
+}
+
+// ExpressionDefault returns a default holding expr, e.g. CURRENT_TIMESTAMP or (UUID()) on 8.0.
+func ExpressionDefault(expr string) ColumnDefault {
+	return ColumnDefault{Kind: DefaultExpression, Value: expr}
+}
+
+// ColumnDef describes a column, used both by table creation and column addition.
+type ColumnDef struct {
+	Name          string
+	Type          string // the column data type, e.g. VARCHAR(20), BIGINT UNSIGNED
+	Default       ColumnDefault
+	PrimaryKey    bool
+	Unique        bool
+	AutoIncrement bool
+	NotNull       bool
+	Comment       string
+	Charset       string
+	Collation     string
+	Generated     string // expression of a generated column, empty for an ordinary column
+	Stored        bool   // a STORED generated column, VIRTUAL otherwise
+	First         bool   // only for column addition, add the column as the first one
+	After         string // only for column addition, add the column after this one
+}
+
+// sql renders the column definition, position is only rendered when withPosition is true
+// because CREATE TABLE does not accept it. A generated column can not have a default.
+func (c *ColumnDef) sql(withPosition bool) (string, error) {
+	if c.Generated != "" && c.Default.Kind != DefaultNone {
+		return "", errGeneratedColumnDefault
+	}
+	s := c.Name + " " + c.Type
+	if c.Charset != "" {
+		s += " CHARACTER SET " + c.Charset
+	}
+	if c.Collation != "" {
+		s += " COLLATE " + c.Collation
+	}
+	if c.Generated != "" {
+		s += " GENERATED ALWAYS AS (" + c.Generated + ")"
+		if c.Stored {
+			s += " STORED"
+		} else {
+			s += " VIRTUAL"
+		}
+	}
+	if c.NotNull {
+		s += " NOT NULL"
+	}
+	if d := c.Default.sql(c.Type); d != "" {
+		s += " DEFAULT " + d
+	}
+	if c.AutoIncrement {
+		s += " AUTO_INCREMENT"
+	}
+	if c.Unique {
+		s += " UNIQUE"
+	}
+	if c.PrimaryKey {
+		s += " PRIMARY KEY"
+	}
+	if c.Comment != "" {
+		s += " COMMENT " + quoteString(c.Comment)
+	}
+	if withPosition {
+		if c.First {
+			s += " FIRST"
+		} else if c.After != "" {
+			s += " AFTER " + c.After
+		}
+	}
+	return s, nil
+}
+
+// sql renders the default value for a column of type colType, return "" if there is no default.
+func (d ColumnDefault) sql(colType string) string {
+	switch d.Kind {
+	case DefaultLiteral:
+		if isNumericType(colType) && (numericLiteral.MatchString(d.Value) || isBareLiteral(d.Value)) {
+			return d.Value
+		}
+		return quoteString(d.Value)
+	case DefaultExpression:
+		return d.Value
+	case DefaultNull:
+		return "NULL"
+	}
+	return ""
+}
+
+// parseDefault parse a default written as plain text, which is what tags and CreateColumnWithConstraint receive.
+// NULL and the current time functions are recognized, anything else is a literal quoted according to the column type.
+func parseDefault(deflt string) ColumnDefault {
+	deflt = strings.Trim(deflt, " ")
+	if deflt == "" {
+		return ColumnDefault{}
+	}
+	upper := strings.ToUpper(deflt)
+	if upper == "NULL" {
+		return NullDefault
+	}
+	for _, fn := range []string{"CURRENT_TIMESTAMP", "NOW(", "LOCALTIME", "LOCALTIMESTAMP"} {
+		if strings.HasPrefix(upper, fn) {
+			return ExpressionDefault(deflt)
+		}
+	}
+	return LiteralDefault(deflt)
+}
+
+// isBareLiteral report whether v is TRUE, FALSE, or a bit or hex literal such as b'1', 0x1F and x'1F',
+// which must not be quoted
+func isBareLiteral(v string) bool {
+	upper := strings.ToUpper(strings.Trim(v, " "))
+	switch {
+	case upper == "TRUE" || upper == "FALSE":
+		return true
+	case strings.HasPrefix(upper, "0B"):
+		return len(upper) > 2 && strings.Trim(upper[2:], "01") == ""
+	case strings.HasPrefix(upper, "0X"):
+		return len(upper) > 2 && strings.Trim(upper[2:], "0123456789ABCDEF") == ""
+	case len(upper) >= 3 && (upper[0] == 'B' || upper[0] == 'X') && upper[1] == '\'' && upper[len(upper)-1] == '\'':
+		digits := "01"
+		if upper[0] == 'X' {
+			digits = "0123456789ABCDEF"
+		}
+		return strings.Trim(upper[2:len(upper)-1], digits) == ""
+	}
+	return false
+}
+
+// isNumericType report whether colType is a numeric data type
+func isNumericType(colType string) bool {
+	base := strings.ToUpper(strings.Trim(colType, " "))
+	if i := strings.IndexAny(base, "( "); i >= 0 {
+		base = base[:i]
+	}
+	switch base {
+	case "TINYINT", "SMALLINT", "MEDIUMINT", "INT", "INTEGER", "BIGINT",
+		"DECIMAL", "DEC", "NUMERIC", "FIXED", "FLOAT", "DOUBLE", "REAL", "BIT", "BOOL", "BOOLEAN":
+		return true
+	}
+	return false
+}
+
+// quoteString quote s as a string literal
+func quoteString(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, "'", `\'`, -1)
+	return "'" + s + "'"
+}
@@ -114,65 +114,60 @@ func CreateColumnIfNotExist(db *sql.DB, schema, column, columnType string) error
 	if strings.Trim(columnType, " ") == "" {
 		return errEmptyParamColType
 	}
-	_, err = db.Exec("ALTER TABLE " + database + "." + table + " ADD " + column + " " + columnType)
-	return err
+	return execDDL(db, database, table, "ALTER TABLE "+database+"."+table+" ADD "+column+" "+columnType)
 }
 
 // CreateColumnWithConstraint create a column with constraint if not exist.
 // Empty param leads to panic.
 func CreateColumnWithConstraint(db *sql.DB, schema, column, columnType, deflt string, isPK, isUniq, isAutoIncr, isNotNull bool) error {
+	return CreateColumnWithDef(db, schema, ColumnDef{
+		Name:          column,
+		Type:          columnType,
+		Default:       parseDefault(deflt),
+		PrimaryKey:    isPK,
+		Unique:        isUniq,
+		AutoIncrement: isAutoIncr,
+		NotNull:       isNotNull,
+	})
+}
+
+// CreateColumnWithDef create a column described by def if not exist.
+func CreateColumnWithDef(db *sql.DB, schema string, def ColumnDef) error {
+	def.Name = strings.Trim(def.Name, " ")
+	if def.Name == "" {
+		return errEmptyParamColumn
+	}
 	database, table, err := parseTableSchema(db, schema)
 	if err != nil {
 		return err
 	}
 	var colexist bool
-	colexist, err = ColumnExist(db, database+"."+table, column)
+	colexist, err = ColumnExist(db, database+"."+table, def.Name)
 	if err != nil {
 		return err
 	}
 	if colexist {
 		return nil
 	}
-	columnType = strings.Trim(columnType, " ")
-	if columnType == "" {
+	def.Type = strings.Trim(def.Type, " ")
+	if def.Type == "" {
 		return errEmptyParamColType
 	}
-	var constraint string
-	if isPK {
-		constraint = " PRIMARY KEY"
-	}
-	if isUniq {
-		constraint += " UNIQUE"
-	}
-	if isAutoIncr {
-		constraint += " AUTO_INCREMENT"
-	}
-	if isNotNull {
-		constraint += " NOT NULL"
-	}
-	deflt = parseDefault(columnType, deflt)
-	if deflt != "" {
-		constraint += " DEFAULT " + deflt
-	}
-	_, err = db.Exec("ALTER TABLE " + database + "." + table + " ADD " + column + " " + columnType + constraint)
-	return err
-}
-
-// parseDefault add single quote to deflt when colType is VARCHAR
-func parseDefault(colType, deflt string) string {
-	deflt = strings.Trim(deflt, " ")
-	if deflt == "" {
-		return ""
-	}
-	if strings.Contains(strings.ToLower(colType), "varchar") {
-		return "'" + deflt + "'"
+	var column string
+	if column, err = def.sql(true); err != nil {
+		return err
 	}
-	return deflt
+	return execDDL(db, database, table, "ALTER TABLE "+database+"."+table+" ADD "+column)
 }
 
-// DropColumnIfExist drop a specific cloumn if exists.
+// DropColumnIfExist drop a specific cloumn if exists, guarded by DefaultDropPolicy
 // Empty param leads to panic.
 func DropColumnIfExist(db *sql.DB, schema, column string) error {
+	return DropColumnIfExistWithPolicy(db, schema, column, &DefaultDropPolicy)
+}
+
+// DropColumnIfExistWithPolicy drop a specific cloumn if exists and p allows it.
+func DropColumnIfExistWithPolicy(db *sql.DB, schema, column string, p *DropPolicy) error {
 	column = strings.Trim(column, " ")
 	if column == "" {
 		return errEmptyParamColumn
@@ -190,6 +185,8 @@ func DropColumnIfExist(db *sql.DB, schema, column string) error {
 	if !isexist {
 		return nil
 	}
-	_, err = db.Exec("ALTER TABLE " + schema + " DROP COLUMN " + column)
-	return err
+	if err = p.checkTable(db, database, table, schema+"."+column); err != nil {
+		return err
+	}
+	return p.execDDL(db, database, table, "ALTER TABLE "+schema+" DROP COLUMN "+column)
 }
@@ -23,6 +23,45 @@ func Test_DescribeColumn(t *testing.T) {
 	fmt.Println(dc)
 }
 
+func Test_ColumnDefSQL(t *testing.T) {
+	cases := []struct {
+		def    ColumnDef
+		expect string
+	}{
+		{ColumnDef{Name: "name", Type: "CHAR(2)", Default: parseDefault("cn"), NotNull: true}, "name CHAR(2) NOT NULL DEFAULT 'cn'"},
+		{ColumnDef{Name: "note", Type: "TEXT", Default: LiteralDefault("it's")}, `note TEXT DEFAULT 'it\'s'`},
+		{ColumnDef{Name: "num", Type: "INT UNSIGNED", Default: parseDefault("10")}, "num INT UNSIGNED DEFAULT 10"},
+		{ColumnDef{Name: "s", Type: "ENUM('a','b')", Default: parseDefault("a")}, "s ENUM('a','b') DEFAULT 'a'"},
+		{ColumnDef{Name: "at", Type: "DATETIME", Default: parseDefault("2018-09-11 10:00:00")}, "at DATETIME DEFAULT '2018-09-11 10:00:00'"},
+		{ColumnDef{Name: "at", Type: "DATETIME", Default: parseDefault("current_timestamp")}, "at DATETIME DEFAULT current_timestamp"},
+		{ColumnDef{Name: "v", Type: "VARCHAR(10)", Default: parseDefault("NULL")}, "v VARCHAR(10) DEFAULT NULL"},
+		{ColumnDef{Name: "v", Type: "VARCHAR(10)", Default: LiteralDefault("")}, "v VARCHAR(10) DEFAULT ''"},
+		{ColumnDef{Name: "id", Type: "BIGINT", PrimaryKey: true, AutoIncrement: true, NotNull: true, First: true}, "id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY FIRST"},
+		{ColumnDef{Name: "n", Type: "VARCHAR(20)", Charset: "utf8mb4", Collation: "utf8mb4_bin", Comment: "user name", After: "id"}, "n VARCHAR(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin COMMENT 'user name' AFTER id"},
+		{ColumnDef{Name: "ok", Type: "TINYINT", Default: parseDefault("true")}, "ok TINYINT DEFAULT true"},
+		{ColumnDef{Name: "s", Type: "VARCHAR(10)", Default: parseDefault("true")}, "s VARCHAR(10) DEFAULT 'true'"},
+		{ColumnDef{Name: "s", Type: "VARCHAR(10)", Default: parseDefault("0x1F")}, "s VARCHAR(10) DEFAULT '0x1F'"},
+		{ColumnDef{Name: "f", Type: "DOUBLE", Default: parseDefault("nan")}, "f DOUBLE DEFAULT 'nan'"},
+		{ColumnDef{Name: "f", Type: "DOUBLE", Default: parseDefault("-1.5e3")}, "f DOUBLE DEFAULT -1.5e3"},
+		{ColumnDef{Name: "ok", Type: "BOOL", Default: LiteralDefault("FALSE")}, "ok BOOL DEFAULT FALSE"},
+		{ColumnDef{Name: "b", Type: "BIT(1)", Default: parseDefault("b'1'")}, "b BIT(1) DEFAULT b'1'"},
+		{ColumnDef{Name: "b", Type: "BIT(8)", Default: LiteralDefault("0x1F")}, "b BIT(8) DEFAULT 0x1F"},
+		{ColumnDef{Name: "s", Type: "VARCHAR(10)", Default: LiteralDefault("true")}, "s VARCHAR(10) DEFAULT 'true'"},
+		{ColumnDef{Name: "s", Type: "VARCHAR(10)", Default: parseDefault("bob")}, "s VARCHAR(10) DEFAULT 'bob'"},
+		{ColumnDef{Name: "total", Type: "INT", Generated: "a+b", Stored: true}, "total INT GENERATED ALWAYS AS (a+b) STORED"},
+	}
+	for _, c := range cases {
+		if s, err := c.def.sql(true); err != nil || s != c.expect {
+			t.Errorf("expect %q, got %q %v", c.expect, s, err)
+		}
+	}
+
+	def := ColumnDef{Name: "total", Type: "INT", Generated: "a+b", Default: LiteralDefault("0")}
+	if _, err := def.sql(false); err != errGeneratedColumnDefault {
+		t.Error(err)
+	}
+}
+
 // func Test_column(t *testing.T) {
 // 	db, err := sql.Open("mysql", constant.Dsn)
 // 	if err != nil {
@@ -2,6 +2,8 @@ package mysql
 
 import (
 	"database/sql"
+	"strconv"
+	"time"
 )
 
 // NumProcess return the number of transaction
@@ -9,3 +11,84 @@ func NumProcess(db *sql.DB) (num int, err error) {
 	err = db.QueryRow("SELECT COUNT(0) FROM information_schema.PROCESSLIST").Scan(&num)
 	return
 }
+
+// Process is a row of information_schema.PROCESSLIST
+type Process struct {
+	ID      uint64
+	User    string
+	Host    string
+	DB      string // empty if no database is selected
+	Command string // e.g. Query, Sleep, Binlog Dump
+	Time    time.Duration
+	State   string
+	Info    string // the statement being executed, empty if none
+}
+
+// ProcessFilter selects processes in ListProcesses, zero fields do not filter.
+type ProcessFilter struct {
+	User         string
+	Database     string
+	MinTime      time.Duration // only processes in their current state for at least MinTime
+	ExcludeSleep bool          // exclude idle connections whose command is Sleep
+	ExcludeSelf  bool          // exclude the connection running ListProcesses
+}
+
+// ListProcesses get the processes matching filter, the longest running first. filter can be nil.
+func ListProcesses(db *sql.DB, filter *ProcessFilter) ([]Process, error) {
+	if filter == nil {
+		filter = &ProcessFilter{}
+	}
+	query := `SELECT ID, USER, HOST, COALESCE(DB, ''), COMMAND, TIME, COALESCE(STATE, ''), COALESCE(INFO, '') 
+			FROM information_schema.PROCESSLIST 
+			WHERE 1 = 1`
+	var args []interface{}
+	if filter.User != "" {
+		query += " AND USER = ?"
+		args = append(args, filter.User)
+	}
+	if filter.Database != "" {
+		query += " AND DB = ?"
+		args = append(args, filter.Database)
+	}
+	if filter.MinTime > 0 {
+		query += " AND TIME >= ?"
+		args = append(args, int64(filter.MinTime/time.Second))
+	}
+	if filter.ExcludeSleep {
+		query += " AND COMMAND <> 'Sleep'"
+	}
+	if filter.ExcludeSelf {
+		query += " AND ID <> CONNECTION_ID()"
+	}
+	rows, err := db.Query(query+" ORDER BY TIME DESC", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var processes []Process
+	for rows.Next() {
+		var (
+			p       Process
+			seconds int64
+		)
+		if err = rows.Scan(&p.ID, &p.User, &p.Host, &p.DB, &p.Command, &seconds, &p.State, &p.Info); err != nil {
+			return nil, err
+		}
+		p.Time = time.Duration(seconds) * time.Second
+		processes = append(processes, p)
+	}
+	return processes, rows.Err()
+}
+
+// KillQuery terminate the statement the connection is executing, leaving the connection intact
+func KillQuery(db *sql.DB, id uint64) error {
+	_, err := db.Exec("KILL QUERY " + strconv.FormatUint(id, 10))
+	return err
+}
+
+// KillConnection terminate the connection and the statement it is executing
+func KillConnection(db *sql.DB, id uint64) error {
+	_, err := db.Exec("KILL CONNECTION " + strconv.FormatUint(id, 10))
+	return err
+}
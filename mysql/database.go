@@ -29,18 +29,158 @@ func CreateDatabaseIfNotExist(db *sql.DB, database string) error {
 	return err
 }
 
-// DropDatabaseIfExist drop a databse if exists
-// Drop the current database if param database is empty
+// DropDatabaseIfExist drop a databse if exists, guarded by DefaultDropPolicy
 func DropDatabaseIfExist(db *sql.DB, database string) error {
+	return DropDatabaseIfExistWithPolicy(db, database, &DefaultDropPolicy)
+}
+
+// DropDatabaseIfExistWithPolicy drop a databse if exists and p allows it.
+// Drop the current database if param database is empty and p.AllowCurrentDatabase is true.
+func DropDatabaseIfExistWithPolicy(db *sql.DB, database string, p *DropPolicy) error {
+	database = strings.Trim(database, " ")
+	var err error
+	if database == "" {
+		if !p.AllowCurrentDatabase {
+			return errEmptyParamDatabase
+		}
+		database, err = getDatabaseName(db)
+		if err != nil {
+			return err
+		}
+	}
+	if err = p.checkDatabase(db, database); err != nil {
+		return err
+	}
+	return p.exec(db, "DROP DATABASE IF EXISTS "+database)
+}
+
+// DatabaseOptions are the options of CREATE DATABASE and ALTER DATABASE, empty fields are left to the server.
+type DatabaseOptions struct {
+	Charset    string // DEFAULT CHARACTER SET
+	Collation  string // DEFAULT COLLATE
+	Encryption *bool  // DEFAULT ENCRYPTION, requires 8.0.16 or newer
+}
+
+// sql renders the options, return errUnsupportedServer if encryption is set on an older server.
+func (o *DatabaseOptions) sql(db *sql.DB) (string, error) {
+	var s string
+	if o.Charset = strings.Trim(o.Charset, " "); o.Charset != "" {
+		s += " DEFAULT CHARACTER SET " + o.Charset
+	}
+	if o.Collation = strings.Trim(o.Collation, " "); o.Collation != "" {
+		s += " DEFAULT COLLATE " + o.Collation
+	}
+	if o.Encryption != nil {
+		v, err := getServerVersion(db)
+		if err != nil {
+			return "", err
+		}
+		if !v.atLeast(8, 0, 16) {
+			return "", errUnsupportedServer
+		}
+		if *o.Encryption {
+			s += " DEFAULT ENCRYPTION 'Y'"
+		} else {
+			s += " DEFAULT ENCRYPTION 'N'"
+		}
+	}
+	return s, nil
+}
+
+// CreateDatabaseWithOptionsIfNotExist create a database with charset, collation and encryption if not exists
+func CreateDatabaseWithOptionsIfNotExist(db *sql.DB, database string, opts DatabaseOptions) error {
+	database = strings.Trim(database, " ")
+	if database == "" {
+		return errEmptyParamDatabase
+	}
+	options, err := opts.sql(db)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec("CREATE DATABASE IF NOT EXISTS " + database + options)
+	return err
+}
+
+// AlterDatabase change charset, collation or encryption of a database.
+// Alter the current database if param database is empty
+func AlterDatabase(db *sql.DB, database string, opts DatabaseOptions) error {
 	database = strings.Trim(database, " ")
+	var err error
 	if database == "" {
-		database, err := getDatabaseName(db)
+		database, err = getDatabaseName(db)
 		if err != nil {
 			return err
 		}
-		_, err = db.Exec("DROP DATABASE " + database)
+	}
+	var options string
+	options, err = opts.sql(db)
+	if err != nil {
 		return err
 	}
-	_, err := db.Exec("DROP DATABASE IF EXISTS " + database)
+	if options == "" {
+		return errEmptyDatabaseOptions
+	}
+	_, err = db.Exec("ALTER DATABASE " + database + options)
 	return err
 }
+
+// DescDatabase is the SCHEMATA row of a database with the aggregate size of its tables
+type DescDatabase struct {
+	Catalog     string // This value is always def.
+	Name        string
+	Charset     string          // DEFAULT_CHARACTER_SET_NAME
+	Collation   string          // DEFAULT_COLLATION_NAME
+	SQLPath     *sql.NullString // This value is always NULL.
+	Encryption  *sql.NullString // YES | NO, NULL before 8.0.16
+	NumTable    uint
+	DataLength  uint64 // sum of DATA_LENGTH of all tables
+	IndexLength uint64 // sum of INDEX_LENGTH of all tables
+	DataFree    uint64 // sum of DATA_FREE of all tables
+}
+
+// DescribeDatabase get the detail information of database. Using current database when param database is empty.
+func DescribeDatabase(db *sql.DB, database string) (*DescDatabase, error) {
+	database = strings.Trim(database, " ")
+	var err error
+	if database == "" {
+		database, err = getDatabaseName(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var v serverVersion
+	v, err = getServerVersion(db)
+	if err != nil {
+		return nil, err
+	}
+	encryption := "NULL"
+	if v.atLeast(8, 0, 16) {
+		encryption = "DEFAULT_ENCRYPTION"
+	}
+
+	dd := &DescDatabase{
+		SQLPath:    &sql.NullString{},
+		Encryption: &sql.NullString{},
+	}
+	err = db.QueryRow(
+		`SELECT CATALOG_NAME, SCHEMA_NAME, DEFAULT_CHARACTER_SET_NAME, DEFAULT_COLLATION_NAME, SQL_PATH, `+encryption+` 
+			FROM information_schema.SCHEMATA 
+			WHERE SCHEMA_NAME = ?`, database,
+	).Scan(&dd.Catalog, &dd.Name, &dd.Charset, &dd.Collation, dd.SQLPath, dd.Encryption)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errDatabaseNotExist
+		}
+		return nil, err
+	}
+
+	err = db.QueryRow(
+		`SELECT COUNT(0), COALESCE(SUM(DATA_LENGTH), 0), COALESCE(SUM(INDEX_LENGTH), 0), COALESCE(SUM(DATA_FREE), 0) 
+			FROM information_schema.TABLES 
+			WHERE TABLE_SCHEMA = ? AND TABLE_TYPE = 'BASE TABLE'`, database,
+	).Scan(&dd.NumTable, &dd.DataLength, &dd.IndexLength, &dd.DataFree)
+	if err != nil {
+		return nil, err
+	}
+	return dd, nil
+}
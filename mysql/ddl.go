@@ -0,0 +1,155 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strconv"
+	"time"
+
+	gomysql "github.com/go-sql-driver/mysql"
+)
+
+// errLockWaitTimeout is the error number of ER_LOCK_WAIT_TIMEOUT
+const errLockWaitTimeout = 1205
+
+// DDLGuard keeps an ALTER TABLE from queuing behind open transactions, during which it blocks every query on the table.
+// Before executing, it looks for other connections holding metadata locks on the table, and runs the statement
+// with a short lock_wait_timeout. When blocked, it retries with exponential backoff and finally gives up.
+type DDLGuard struct {
+	LockWaitTimeout time.Duration // lock_wait_timeout of the DDL session, rounded down to seconds and at least 1s
+	Retries         int           // times to retry after the first attempt
+	Backoff         time.Duration // wait before the first retry, doubled for each retry
+}
+
+// DefaultDDLGuard guards the ALTER TABLE, CREATE INDEX and DROP INDEX issued by this package when it is not nil,
+// such as CreateColumnIfNotExist and CreateIndexIfNotExist.
+var DefaultDDLGuard *DDLGuard
+
+// DDLBlocker is another connection holding a metadata lock on a table
+type DDLBlocker struct {
+	Thread   uint64 // ID of the connection in PROCESSLIST
+	LockType string // e.g. SHARED_READ, SHARED_WRITE
+	TrxID    string // empty when the connection has no InnoDB transaction
+	TrxAge   time.Duration
+}
+
+// execDDL execute query on database.table, through DefaultDDLGuard if it is not nil
+func execDDL(db *sql.DB, database, table, query string) error {
+	if DefaultDDLGuard == nil {
+		_, err := db.Exec(query)
+		return err
+	}
+	return DefaultDDLGuard.exec(db, database, table, query)
+}
+
+// Exec execute a DDL statement on a table, return errDDLBlocked if the table is still locked after all retries.
+// Use the currently selected database if schema does not contain database.
+func (g *DDLGuard) Exec(db *sql.DB, schema, query string) error {
+	database, table, err := parseTableSchema(db, schema)
+	if err != nil {
+		return err
+	}
+	return g.exec(db, database, table, query)
+}
+
+// exec execute query on database.table with retries
+func (g *DDLGuard) exec(db *sql.DB, database, table, query string) error {
+	backoff := g.Backoff
+	for attempt := 0; ; attempt++ {
+		blockers, err := ddlBlockers(db, database, table)
+		if err != nil {
+			return err
+		}
+		if len(blockers) == 0 {
+			err = g.execWithTimeout(db, query)
+			if err == nil || !isLockWaitTimeout(err) {
+				return err
+			}
+		}
+		if attempt >= g.Retries {
+			return errDDLBlocked
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// execWithTimeout execute query on a pinned connection with lock_wait_timeout set, and restore it after
+func (g *DDLGuard) execWithTimeout(db *sql.DB, query string) error {
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var previous int64
+	if err = conn.QueryRowContext(ctx, "SELECT @@SESSION.lock_wait_timeout").Scan(&previous); err != nil {
+		return err
+	}
+	timeout := int64(g.LockWaitTimeout / time.Second)
+	if timeout < 1 {
+		timeout = 1
+	}
+	if _, err = conn.ExecContext(ctx, "SET SESSION lock_wait_timeout = "+strconv.FormatInt(timeout, 10)); err != nil {
+		return err
+	}
+	_, err = conn.ExecContext(ctx, query)
+	if _, restoreErr := conn.ExecContext(ctx, "SET SESSION lock_wait_timeout = "+strconv.FormatInt(previous, 10)); restoreErr != nil {
+		// never give a connection with a short timeout back to the pool
+		conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+		if err == nil {
+			err = restoreErr
+		}
+	}
+	return err
+}
+
+// DDLBlockers get the other connections holding metadata locks on a table with their InnoDB transactions.
+// It relies on performance_schema.metadata_locks, whose instrument wait/lock/metadata/sql/mdl is disabled by default before 8.0.
+// Use the currently selected database if schema does not contain database.
+func DDLBlockers(db *sql.DB, schema string) ([]DDLBlocker, error) {
+	database, table, err := parseTableSchema(db, schema)
+	if err != nil {
+		return nil, err
+	}
+	return ddlBlockers(db, database, table)
+}
+
+// ddlBlockers get the connections blocking DDL on database.table
+func ddlBlockers(db *sql.DB, database, table string) ([]DDLBlocker, error) {
+	rows, err := db.Query(
+		`SELECT t.PROCESSLIST_ID, m.LOCK_TYPE, COALESCE(x.trx_id, ''), 
+			COALESCE(TIMESTAMPDIFF(SECOND, x.trx_started, NOW()), 0) 
+			FROM performance_schema.metadata_locks m 
+			JOIN performance_schema.threads t ON t.THREAD_ID = m.OWNER_THREAD_ID 
+			LEFT JOIN information_schema.INNODB_TRX x ON x.trx_mysql_thread_id = t.PROCESSLIST_ID 
+			WHERE m.OBJECT_TYPE = 'TABLE' AND m.OBJECT_SCHEMA = ? AND m.OBJECT_NAME = ? 
+				AND m.LOCK_STATUS = 'GRANTED' AND t.PROCESSLIST_ID <> CONNECTION_ID()`, database, table,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var blockers []DDLBlocker
+	for rows.Next() {
+		var (
+			b       DDLBlocker
+			seconds int64
+		)
+		if err = rows.Scan(&b.Thread, &b.LockType, &b.TrxID, &seconds); err != nil {
+			return nil, err
+		}
+		b.TrxAge = time.Duration(seconds) * time.Second
+		blockers = append(blockers, b)
+	}
+	return blockers, rows.Err()
+}
+
+// isLockWaitTimeout report whether err is ER_LOCK_WAIT_TIMEOUT
+func isLockWaitTimeout(err error) bool {
+	e, ok := err.(*gomysql.MySQLError)
+	return ok && e.Number == errLockWaitTimeout
+}
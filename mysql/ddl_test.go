@@ -0,0 +1,17 @@
+package mysql
+
+import (
+	"errors"
+	"testing"
+
+	gomysql "github.com/go-sql-driver/mysql"
+)
+
+func Test_isLockWaitTimeout(t *testing.T) {
+	if !isLockWaitTimeout(&gomysql.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded; try restarting transaction"}) {
+		t.Error("expect lock wait timeout")
+	}
+	if isLockWaitTimeout(&gomysql.MySQLError{Number: 1062}) || isLockWaitTimeout(errors.New("1205")) {
+		t.Error("expect other error")
+	}
+}
@@ -0,0 +1,184 @@
+package mysql
+
+import (
+	"database/sql"
+	"sort"
+	"time"
+)
+
+// DigestOrder is the order of statement digests in a report
+type DigestOrder int
+
+// Orders of statement digests
+const (
+	DigestByLatency    DigestOrder = iota // total latency
+	DigestByAvgLatency                    // average latency
+	DigestByCount                         // number of executions
+)
+
+// digestOrderColumns are the columns of events_statements_summary_by_digest for each DigestOrder
+var digestOrderColumns = []string{
+	DigestByLatency:    "SUM_TIMER_WAIT",
+	DigestByAvgLatency: "AVG_TIMER_WAIT",
+	DigestByCount:      "COUNT_STAR",
+}
+
+const digestColumns = `SCHEMA_NAME, DIGEST, DIGEST_TEXT, COUNT_STAR, SUM_TIMER_WAIT, AVG_TIMER_WAIT, MAX_TIMER_WAIT, 
+	SUM_ROWS_EXAMINED, SUM_ROWS_SENT, SUM_NO_INDEX_USED, SUM_NO_GOOD_INDEX_USED, FIRST_SEEN, LAST_SEEN`
+
+// StatementDigest is the summary of a normalized statement from performance_schema.events_statements_summary_by_digest.
+// Schema and Digest are empty for the row counting statements which did not fit in the table.
+type StatementDigest struct {
+	Schema          string
+	Digest          string
+	Text            string
+	Count           uint64
+	TotalLatency    time.Duration
+	AvgLatency      time.Duration
+	MaxLatency      time.Duration
+	RowsExamined    uint64
+	RowsSent        uint64
+	NoIndexUsed     uint64 // executions which did a full table scan
+	NoGoodIndexUsed uint64 // executions which found no good index to use
+	FirstSeen       *time.Time
+	LastSeen        *time.Time
+}
+
+// key identify a digest, the same statement in different schemas has different digest rows
+func (d *StatementDigest) key() string {
+	return d.Schema + "\x00" + d.Digest
+}
+
+// TopDigests get the n statement digests with the highest latency or count, n <= 0 means all.
+func TopDigests(db *sql.DB, order DigestOrder, n int) ([]StatementDigest, error) {
+	column := digestOrderColumns[DigestByLatency]
+	if order >= 0 && int(order) < len(digestOrderColumns) {
+		column = digestOrderColumns[order]
+	}
+	query := "SELECT " + digestColumns + " FROM performance_schema.events_statements_summary_by_digest ORDER BY " + column + " DESC"
+	var args []interface{}
+	if n > 0 {
+		query += " LIMIT ?"
+		args = append(args, n)
+	}
+	return queryDigests(db, query, args...)
+}
+
+// ResetDigests clear the statement digests, to start collecting from now
+func ResetDigests(db *sql.DB) error {
+	_, err := db.Exec("TRUNCATE TABLE performance_schema.events_statements_summary_by_digest")
+	return err
+}
+
+// DigestSnapshot is all the statement digests at a moment
+type DigestSnapshot struct {
+	Time    time.Time
+	Digests []StatementDigest
+}
+
+// GetDigests take a snapshot of all the statement digests
+func GetDigests(db *sql.DB) (*DigestSnapshot, error) {
+	now := time.Now()
+	digests, err := queryDigests(db, "SELECT "+digestColumns+" FROM performance_schema.events_statements_summary_by_digest")
+	if err != nil {
+		return nil, err
+	}
+	return &DigestSnapshot{Time: now, Digests: digests}, nil
+}
+
+// DigestDiff is the statements executed between two snapshots
+type DigestDiff struct {
+	Interval time.Duration
+	Digests  []StatementDigest
+}
+
+// DiffDigests compute the statements executed from prev to cur. Digests which were reset in between,
+// seen by a changed FirstSeen or any counter going down, are counted from zero. MaxLatency cannot be diffed
+// and is the one of cur.
+func DiffDigests(prev, cur *DigestSnapshot) *DigestDiff {
+	previous := make(map[string]*StatementDigest, len(prev.Digests))
+	for i := range prev.Digests {
+		previous[prev.Digests[i].key()] = &prev.Digests[i]
+	}
+
+	d := &DigestDiff{Interval: cur.Time.Sub(prev.Time)}
+	for _, c := range cur.Digests {
+		if p, ok := previous[c.key()]; ok && c.continues(p) {
+			c.Count -= p.Count
+			c.TotalLatency -= p.TotalLatency
+			c.RowsExamined -= p.RowsExamined
+			c.RowsSent -= p.RowsSent
+			c.NoIndexUsed -= p.NoIndexUsed
+			c.NoGoodIndexUsed -= p.NoGoodIndexUsed
+		}
+		if c.Count == 0 {
+			continue
+		}
+		c.AvgLatency = c.TotalLatency / time.Duration(c.Count)
+		d.Digests = append(d.Digests, c)
+	}
+	return d
+}
+
+// continues report whether d accumulates on p, i.e. the digest was not reset since p was taken
+func (d *StatementDigest) continues(p *StatementDigest) bool {
+	if d.FirstSeen != nil && p.FirstSeen != nil && !d.FirstSeen.Equal(*p.FirstSeen) {
+		return false
+	}
+	return d.Count >= p.Count && d.TotalLatency >= p.TotalLatency && d.RowsExamined >= p.RowsExamined &&
+		d.RowsSent >= p.RowsSent && d.NoIndexUsed >= p.NoIndexUsed && d.NoGoodIndexUsed >= p.NoGoodIndexUsed
+}
+
+// Top get the n digests with the highest latency or count in the diff, n <= 0 means all.
+func (d *DigestDiff) Top(order DigestOrder, n int) []StatementDigest {
+	digests := append([]StatementDigest(nil), d.Digests...)
+	sortDigests(digests, order)
+	if n > 0 && n < len(digests) {
+		digests = digests[:n]
+	}
+	return digests
+}
+
+// sortDigests sort digests by order descending
+func sortDigests(digests []StatementDigest, order DigestOrder) {
+	sort.SliceStable(digests, func(i, j int) bool {
+		switch order {
+		case DigestByAvgLatency:
+			return digests[i].AvgLatency > digests[j].AvgLatency
+		case DigestByCount:
+			return digests[i].Count > digests[j].Count
+		}
+		return digests[i].TotalLatency > digests[j].TotalLatency
+	})
+}
+
+// queryDigests run a query selecting digestColumns
+func queryDigests(db *sql.DB, query string, args ...interface{}) ([]StatementDigest, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var digests []StatementDigest
+	for rows.Next() {
+		var (
+			d                    StatementDigest
+			schema, digest, text sql.NullString
+			total, avg, max      uint64
+		)
+		if err = rows.Scan(&schema, &digest, &text, &d.Count, &total, &avg, &max,
+			&d.RowsExamined, &d.RowsSent, &d.NoIndexUsed, &d.NoGoodIndexUsed, nullTime{&d.FirstSeen}, nullTime{&d.LastSeen}); err != nil {
+			return nil, err
+		}
+		d.Schema, d.Digest, d.Text = schema.String, digest.String, text.String
+		d.TotalLatency, d.AvgLatency, d.MaxLatency = picoseconds(total), picoseconds(avg), picoseconds(max)
+		digests = append(digests, d)
+	}
+	return digests, rows.Err()
+}
+
+// picoseconds convert a timer of performance_schema, in picoseconds, to a duration
+func picoseconds(ps uint64) time.Duration {
+	return time.Duration(ps / 1000)
+}
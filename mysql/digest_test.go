@@ -0,0 +1,66 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_DiffDigests(t *testing.T) {
+	now := time.Now()
+	prev := &DigestSnapshot{Time: now, Digests: []StatementDigest{
+		{Schema: "a", Digest: "d1", Count: 10, TotalLatency: 10 * time.Second, RowsExamined: 100},
+		{Schema: "a", Digest: "d2", Count: 5, TotalLatency: time.Second},
+		{Schema: "a", Digest: "d3", Count: 100, TotalLatency: 100 * time.Second},
+	}}
+	cur := &DigestSnapshot{Time: now.Add(time.Minute), Digests: []StatementDigest{
+		{Schema: "a", Digest: "d1", Count: 14, TotalLatency: 30 * time.Second, RowsExamined: 180},
+		{Schema: "a", Digest: "d2", Count: 5, TotalLatency: time.Second},
+		{Schema: "a", Digest: "d3", Count: 3, TotalLatency: 3 * time.Second},
+		{Schema: "b", Digest: "d1", Count: 50, TotalLatency: 5 * time.Second},
+	}}
+
+	d := DiffDigests(prev, cur)
+	if d.Interval != time.Minute || len(d.Digests) != 3 {
+		t.Fatal(d)
+	}
+
+	top := d.Top(DigestByLatency, 2)
+	if len(top) != 2 || top[0].Digest != "d1" || top[0].Schema != "a" || top[1].Schema != "b" {
+		t.Fatal(top)
+	}
+	if top[0].Count != 4 || top[0].TotalLatency != 20*time.Second || top[0].AvgLatency != 5*time.Second || top[0].RowsExamined != 80 {
+		t.Error(top[0])
+	}
+
+	if top = d.Top(DigestByCount, 0); len(top) != 3 || top[0].Schema != "b" || top[2].Digest != "d3" {
+		t.Error(top)
+	}
+	if top = d.Top(DigestByAvgLatency, 1); top[0].Digest != "d1" || top[0].Schema != "a" {
+		t.Error(top)
+	}
+}
+
+func Test_DiffDigestsReset(t *testing.T) {
+	now := time.Now()
+	before, after := now.Add(-time.Hour), now.Add(-time.Second)
+	prev := &DigestSnapshot{Time: now, Digests: []StatementDigest{
+		{Schema: "a", Digest: "d1", Count: 10, TotalLatency: time.Second, RowsExamined: 1000, FirstSeen: &before},
+		{Schema: "a", Digest: "d2", Count: 10, TotalLatency: time.Second, RowsSent: 100},
+	}}
+	cur := &DigestSnapshot{Time: now.Add(time.Minute), Digests: []StatementDigest{
+		// reset in between, the count grew past the old one but the rows examined did not
+		{Schema: "a", Digest: "d1", Count: 20, TotalLatency: 2 * time.Second, RowsExamined: 10, FirstSeen: &after},
+		{Schema: "a", Digest: "d2", Count: 20, TotalLatency: 2 * time.Second, RowsSent: 50},
+	}}
+	for _, d := range DiffDigests(prev, cur).Digests {
+		if d.Count != 20 || d.RowsExamined > 10 || d.RowsSent > 50 {
+			t.Error(d)
+		}
+	}
+}
+
+func Test_picoseconds(t *testing.T) {
+	if d := picoseconds(1500000000); d != 1500*time.Microsecond {
+		t.Error(d)
+	}
+}
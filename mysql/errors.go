@@ -16,9 +16,55 @@ var (
 	errEmptyParamColumn   = errors.New("param column is empty")
 	errEmptyParamColType  = errors.New("param columnType is empty")
 	errEmptyParamIndex    = errors.New("param index is empty")
+	errEmptyParamEvent    = errors.New("param event is empty")
+	errEmptyParamBody     = errors.New("param body is empty")
+	errEmptyParamTrigger  = errors.New("param trigger is empty")
+	errEmptyParamQuery    = errors.New("param query is empty")
+	errEmptyParamRoutine  = errors.New("param routine is empty")
 
 	errDropedDatabaseNotExist = errors.New("drop a database that does not exist")
 	errDropedTableNotExist    = errors.New("drop a table that does not exist")
 	errColumnNotExist         = errors.New("drop a column that does not exist")
+	errDatabaseNotExist       = errors.New("database does not exist")
+	errEventNotExist          = errors.New("event does not exist")
+	errTriggerNotExist        = errors.New("trigger does not exist")
+	errViewNotExist           = errors.New("view does not exist")
+	errRoutineNotExist        = errors.New("routine does not exist")
+	errVariableNotExist       = errors.New("variable does not exist")
 	errDropedIndexNotExist    = errors.New("drop a index that does not exist")
+
+	errDropSystemDatabase = errors.New("refuse to drop a system database or anything in it")
+	errDropNotEmpty       = errors.New("refuse to drop a database or table that is not empty")
+	errDropTooManyTables  = errors.New("refuse to drop a database with too many tables")
+	errDropTooManyRows    = errors.New("refuse to drop a database or table with too many rows")
+	errDropNotConfirmed   = errors.New("refuse to drop without confirmation")
+	errDropFromRecycleBin = errors.New("refuse to move a table of the recycle bin into itself")
+	errEmptyRecycleBin    = errors.New("recycle bin database is empty")
+
+	errDroppedTableNotInRecycleBin = errors.New("table is not in the recycle bin")
+
+	errInvalidEventSchedule = errors.New("event schedule needs either At or Interval and Field")
+	errInvalidEventStatus   = errors.New("unknown event status")
+	errInvalidEventInterval = errors.New("unknown event interval")
+
+	errInvalidTriggerTiming = errors.New("trigger timing must be BEFORE or AFTER")
+	errInvalidTriggerEvent  = errors.New("trigger event must be INSERT, UPDATE or DELETE")
+	errTriggerOrderConflict = errors.New("trigger can not both follow and precede")
+
+	errInvalidRoutineType = errors.New("routine type must be PROCEDURE or FUNCTION")
+	errInvalidParamMode   = errors.New("parameter mode must be IN, OUT or INOUT")
+
+	errInvalidVariableName = errors.New("invalid variable name")
+	errInvalidBoolValue    = errors.New("invalid boolean value")
+	errInvalidSizeValue    = errors.New("invalid size value")
+
+	errDDLBlocked = errors.New("ddl is blocked by connections holding metadata locks on the table")
+
+	errEmptyDatabaseOptions = errors.New("no database option is specified")
+	errUnsupportedServer    = errors.New("not supported by the server version")
+	errNoSinglePrimaryKey   = errors.New("chunked copy requires a single column primary key")
+
+	errGeneratedColumnDefault = errors.New("generated column can not have a default value")
+	errEmptyParamReturns      = errors.New("param returns of function is empty")
+	errRoutineNotRestorable   = errors.New("routine definition is not readable, it could not be restored if the replacement failed")
 )
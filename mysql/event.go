@@ -2,7 +2,9 @@ package mysql
 
 import (
 	"database/sql"
+	"strconv"
 	"strings"
+	"time"
 )
 
 const (
@@ -11,6 +13,17 @@ const (
 	EventStatusSlavesideDisable = "SLAVESIDE_DISABLED"
 )
 
+// STATUS values in INFORMATION_SCHEMA.EVENTS, the third one is EventStatusSlavesideDisable
+const (
+	EventStatusEnabled  = "ENABLED"
+	EventStatusDisabled = "DISABLED"
+)
+
+// eventColumns are the columns scanned into DescEvent
+const eventColumns = `EVENT_SCHEMA, EVENT_NAME, DEFINER, TIME_ZONE, EVENT_BODY, EVENT_DEFINITION, EVENT_TYPE, 
+	EXECUTE_AT, INTERVAL_VALUE, INTERVAL_FIELD, SQL_MODE, STARTS, ENDS, STATUS, ON_COMPLETION, 
+	CREATED, LAST_ALTERED, LAST_EXECUTED, EVENT_COMMENT`
+
 var (
 	eventSQLs = []string{
 		`SELECT STATUS 
@@ -27,6 +40,20 @@ var (
 		`SELECT COUNT(0)
 			FROM INFORMATION_SCHEMA.EVENTS
 			WHERE EVENT_SCHEMA=? AND STATUS=?`,
+
+		`SELECT ` + eventColumns + `
+			FROM INFORMATION_SCHEMA.EVENTS 
+			WHERE EVENT_SCHEMA=? AND EVENT_NAME=?`,
+
+		`SELECT ` + eventColumns + `
+			FROM INFORMATION_SCHEMA.EVENTS 
+			WHERE EVENT_SCHEMA=? 
+			ORDER BY EVENT_NAME`,
+
+		`SELECT ` + eventColumns + `
+			FROM INFORMATION_SCHEMA.EVENTS 
+			WHERE EVENT_SCHEMA=? AND STATUS=? 
+			ORDER BY EVENT_NAME`,
 	}
 )
 
@@ -89,3 +116,414 @@ func NumEventWithDBAndStatus(db *sql.DB, database, status string) (uint, error)
 	err = db.QueryRow(eventSQLs[3], database, status).Scan(&num)
 	return num, err
 }
+
+// DescEvent is the detail information of an event
+type DescEvent struct {
+	Schema        string
+	Name          string
+	Definer       string
+	TimeZone      string
+	Body          string // the language used in the body, always SQL
+	Definition    string // the statement executed by the event
+	Type          string // ONE TIME | RECURRING
+	ExecuteAt     *time.Time
+	IntervalValue *sql.NullString
+	IntervalField *sql.NullString
+	SQLMode       string
+	Starts        *time.Time
+	Ends          *time.Time
+	Status        string // ENABLED | DISABLED | SLAVESIDE_DISABLED
+	OnCompletion  string // PRESERVE | NOT PRESERVE
+	Created       *time.Time
+	LastAltered   *time.Time
+	LastExecuted  *time.Time
+	Comment       string
+}
+
+// scanEvent scan a row selected with eventColumns
+func scanEvent(s scanner) (*DescEvent, error) {
+	de := &DescEvent{
+		IntervalValue: &sql.NullString{},
+		IntervalField: &sql.NullString{},
+	}
+	err := s.Scan(&de.Schema, &de.Name, &de.Definer, &de.TimeZone, &de.Body, &de.Definition, &de.Type,
+		nullTime{&de.ExecuteAt}, de.IntervalValue, de.IntervalField, &de.SQLMode, nullTime{&de.Starts}, nullTime{&de.Ends},
+		&de.Status, &de.OnCompletion, nullTime{&de.Created}, nullTime{&de.LastAltered}, nullTime{&de.LastExecuted}, &de.Comment,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return de, nil
+}
+
+// DescribeEvent get the detail information of event. Using current database when param database is empty.
+func DescribeEvent(db *sql.DB, database, event string) (*DescEvent, error) {
+	schema, err := eventSchema(db, database, event)
+	if err != nil {
+		return nil, err
+	}
+	database, event = splitSchema(schema)
+	var de *DescEvent
+	de, err = scanEvent(db.QueryRow(eventSQLs[4], database, event))
+	if err == sql.ErrNoRows {
+		return nil, errEventNotExist
+	}
+	return de, err
+}
+
+// ListEvents get the detail information of events in database, with specific status if param status is not empty.
+// Using current database when param database is empty.
+func ListEvents(db *sql.DB, database, status string) ([]*DescEvent, error) {
+	database = strings.Trim(database, " ")
+	var err error
+	if database == "" {
+		database, err = getDatabaseName(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var rows *sql.Rows
+	status = strings.Trim(status, " ")
+	if status == "" {
+		rows, err = db.Query(eventSQLs[5], database)
+	} else {
+		rows, err = db.Query(eventSQLs[6], database, status)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*DescEvent
+	for rows.Next() {
+		var de *DescEvent
+		if de, err = scanEvent(rows); err != nil {
+			return nil, err
+		}
+		events = append(events, de)
+	}
+	return events, rows.Err()
+}
+
+// EventSchedule is the schedule of an event, set At for a one-time event,
+// or Interval and Field for a recurring event.
+// Times are written in their own location and interpreted in the session time zone by the server.
+type EventSchedule struct {
+	At       time.Time // AT, one-time event
+	Interval string    // EVERY, e.g. 1, or '1:30' when Field is MINUTE_SECOND
+	Field    string    // unit of Interval, e.g. SECOND, HOUR, DAY, MINUTE_SECOND
+	Starts   time.Time // optional STARTS of recurring event
+	Ends     time.Time // optional ENDS of recurring event
+}
+
+// sql renders the ON SCHEDULE clause
+func (s *EventSchedule) sql() (string, error) {
+	if !s.At.IsZero() {
+		return " ON SCHEDULE AT " + quoteTime(s.At), nil
+	}
+	interval, field := strings.Trim(s.Interval, " "), strings.Trim(s.Field, " ")
+	if interval == "" || field == "" {
+		return "", errInvalidEventSchedule
+	}
+	if _, err := strconv.ParseFloat(interval, 64); err != nil {
+		interval = quoteString(interval)
+	}
+	sql := " ON SCHEDULE EVERY " + interval + " " + strings.ToUpper(field)
+	if !s.Starts.IsZero() {
+		sql += " STARTS " + quoteTime(s.Starts)
+	}
+	if !s.Ends.IsZero() {
+		sql += " ENDS " + quoteTime(s.Ends)
+	}
+	return sql, nil
+}
+
+// EventOptions are the optional clauses of CREATE EVENT
+type EventOptions struct {
+	Preserve bool   // ON COMPLETION PRESERVE, the event is dropped after its last execution otherwise
+	Status   string // EventStatusEnable, EventStatusDisable or EventStatusSlavesideDisable, ENABLE if empty
+	Comment  string
+}
+
+// eventStatusSQL renders status for CREATE EVENT and ALTER EVENT
+func eventStatusSQL(status string) (string, error) {
+	switch strings.ToUpper(strings.Trim(status, " ")) {
+	case "":
+		return "", nil
+	case EventStatusEnable:
+		return " ENABLE", nil
+	case EventStatusDisable:
+		return " DISABLE", nil
+	case EventStatusSlavesideDisable:
+		return " DISABLE ON SLAVE", nil
+	}
+	return "", errInvalidEventStatus
+}
+
+// eventSchema get database.event, using current database when param database is empty.
+func eventSchema(db *sql.DB, database, event string) (string, error) {
+	return objectSchema(db, database, event, errEmptyParamEvent)
+}
+
+// CreateEventIfNotExist create an event running body on schedule if not exists.
+// Using current database when param database is empty, opts can be nil.
+func CreateEventIfNotExist(db *sql.DB, database, event string, schedule EventSchedule, body string, opts *EventOptions) error {
+	schema, err := eventSchema(db, database, event)
+	if err != nil {
+		return err
+	}
+	var clauses string
+	if clauses, err = eventClauses(schedule, body, opts, false); err != nil {
+		return err
+	}
+	_, err = db.Exec("CREATE EVENT IF NOT EXISTS " + schema + clauses)
+	return err
+}
+
+// eventClauses renders the clauses from ON SCHEDULE to DO of CREATE EVENT or ALTER EVENT.
+// ALTER EVENT always writes ON COMPLETION, status and COMMENT so that they are reset to opts, empty status meaning ENABLE.
+func eventClauses(schedule EventSchedule, body string, opts *EventOptions, alter bool) (string, error) {
+	body = strings.Trim(body, " ")
+	if body == "" {
+		return "", errEmptyParamBody
+	}
+	if opts == nil {
+		opts = &EventOptions{}
+	}
+	scheduleSQL, err := schedule.sql()
+	if err != nil {
+		return "", err
+	}
+	var statusSQL string
+	if statusSQL, err = eventStatusSQL(opts.Status); err != nil {
+		return "", err
+	}
+	if statusSQL == "" && alter {
+		statusSQL = " ENABLE"
+	}
+	clauses := scheduleSQL
+	if opts.Preserve {
+		clauses += " ON COMPLETION PRESERVE"
+	} else if alter {
+		clauses += " ON COMPLETION NOT PRESERVE"
+	}
+	clauses += statusSQL
+	if opts.Comment != "" || alter {
+		clauses += " COMMENT " + quoteString(opts.Comment)
+	}
+	return clauses + " DO " + body, nil
+}
+
+// AlterEventSchedule change the schedule of an event. Using current database when param database is empty.
+func AlterEventSchedule(db *sql.DB, database, event string, schedule EventSchedule) error {
+	schema, err := eventSchema(db, database, event)
+	if err != nil {
+		return err
+	}
+	var scheduleSQL string
+	if scheduleSQL, err = schedule.sql(); err != nil {
+		return err
+	}
+	_, err = db.Exec("ALTER EVENT " + schema + scheduleSQL)
+	return err
+}
+
+// EnableEvent enable an event. Using current database when param database is empty.
+func EnableEvent(db *sql.DB, database, event string) error {
+	return alterEventStatus(db, database, event, EventStatusEnable)
+}
+
+// DisableEvent disable an event. Using current database when param database is empty.
+func DisableEvent(db *sql.DB, database, event string) error {
+	return alterEventStatus(db, database, event, EventStatusDisable)
+}
+
+// alterEventStatus change the status of an event
+func alterEventStatus(db *sql.DB, database, event, status string) error {
+	schema, err := eventSchema(db, database, event)
+	if err != nil {
+		return err
+	}
+	var statusSQL string
+	if statusSQL, err = eventStatusSQL(status); err != nil {
+		return err
+	}
+	_, err = db.Exec("ALTER EVENT " + schema + statusSQL)
+	return err
+}
+
+// DropEventIfExist drop an event if exists. Using current database when param database is empty.
+func DropEventIfExist(db *sql.DB, database, event string) error {
+	schema, err := eventSchema(db, database, event)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec("DROP EVENT IF EXISTS " + schema)
+	return err
+}
+
+// RenameEvent rename an event, newName can be database.event to move it into another database.
+// Using current database when param database is empty.
+func RenameEvent(db *sql.DB, database, event, newName string) error {
+	schema, err := eventSchema(db, database, event)
+	if err != nil {
+		return err
+	}
+	newName = strings.Trim(newName, " ")
+	if newName == "" {
+		return errEmptyParamEvent
+	}
+	if !strings.Contains(newName, ".") {
+		newName = schema[:strings.Index(schema, ".")+1] + newName
+	}
+	_, err = db.Exec("ALTER EVENT " + schema + " RENAME TO " + newName)
+	return err
+}
+
+// Values of the event_scheduler system variable
+const (
+	EventSchedulerOn       = "ON"
+	EventSchedulerOff      = "OFF"
+	EventSchedulerDisabled = "DISABLED" // the scheduler is disabled at startup and can not be turned on at runtime
+)
+
+// EventSchedulerStatus get the value of global variable event_scheduler, events never fire unless it is ON.
+func EventSchedulerStatus(db *sql.DB) (string, error) {
+	var status string
+	err := db.QueryRow("SELECT @@GLOBAL.event_scheduler").Scan(&status)
+	return strings.ToUpper(status), err
+}
+
+// SetEventScheduler turn the event scheduler on or off, requires SUPER or SYSTEM_VARIABLES_ADMIN.
+func SetEventScheduler(db *sql.DB, on bool) error {
+	status := EventSchedulerOff
+	if on {
+		status = EventSchedulerOn
+	}
+	_, err := db.Exec("SET GLOBAL event_scheduler = " + status)
+	return err
+}
+
+// StalledEvent is an enabled recurring event which has not run for longer than its interval
+type StalledEvent struct {
+	Schema       string
+	Name         string
+	Interval     time.Duration // approximate for MONTH, QUARTER and YEAR based intervals
+	LastExecuted *time.Time    // nil if the event never executed
+	Overdue      time.Duration // time since the execution that was expected
+}
+
+// EventHealth is the result of CheckEventHealth
+type EventHealth struct {
+	Scheduler string // value of event_scheduler
+	Stalled   []StalledEvent
+}
+
+// Healthy report whether the scheduler is on and no event is stalled
+func (h *EventHealth) Healthy() bool {
+	return h.Scheduler == EventSchedulerOn && len(h.Stalled) == 0
+}
+
+// CheckEventHealth check the event scheduler and find enabled recurring events in all databases
+// whose last execution, or start if never executed, is older than their interval plus grace.
+// Elapsed time is computed by the server with NOW() in the session time zone.
+func CheckEventHealth(db *sql.DB, grace time.Duration) (*EventHealth, error) {
+	status, err := EventSchedulerStatus(db)
+	if err != nil {
+		return nil, err
+	}
+	health := &EventHealth{Scheduler: status}
+
+	rows, err := db.Query(
+		`SELECT EVENT_SCHEMA, EVENT_NAME, INTERVAL_VALUE, INTERVAL_FIELD, LAST_EXECUTED, 
+			TIMESTAMPDIFF(SECOND, COALESCE(LAST_EXECUTED, STARTS, CREATED), NOW()) 
+			FROM INFORMATION_SCHEMA.EVENTS 
+			WHERE STATUS = ? AND EVENT_TYPE = 'RECURRING' 
+				AND (STARTS IS NULL OR STARTS <= NOW()) AND (ENDS IS NULL OR ENDS > NOW())`, EventStatusEnabled,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			e              StalledEvent
+			value, field   string
+			elapsedSeconds sql.NullInt64
+		)
+		if err = rows.Scan(&e.Schema, &e.Name, &value, &field, nullTime{&e.LastExecuted}, &elapsedSeconds); err != nil {
+			return nil, err
+		}
+		if e.Interval, err = intervalDuration(value, field); err != nil {
+			return nil, err
+		}
+		elapsed := time.Duration(elapsedSeconds.Int64) * time.Second
+		if elapsed > e.Interval+grace {
+			e.Overdue = elapsed - e.Interval
+			health.Stalled = append(health.Stalled, e)
+		}
+	}
+	return health, rows.Err()
+}
+
+// intervalUnits are the units of interval fields in descending order
+var intervalUnits = []struct {
+	name     string
+	duration time.Duration
+}{
+	{"YEAR", 365 * 24 * time.Hour},
+	{"QUARTER", 91 * 24 * time.Hour},
+	{"MONTH", 30 * 24 * time.Hour},
+	{"WEEK", 7 * 24 * time.Hour},
+	{"DAY", 24 * time.Hour},
+	{"HOUR", time.Hour},
+	{"MINUTE", time.Minute},
+	{"SECOND", time.Second},
+	{"MICROSECOND", time.Microsecond},
+}
+
+// intervalDuration convert INTERVAL_VALUE and INTERVAL_FIELD of an event to a duration.
+// Compound fields such as DAY_HOUR take values like '1 12', missing leading parts are zero.
+func intervalDuration(value, field string) (time.Duration, error) {
+	units := strings.SplitN(strings.ToUpper(strings.Trim(field, " ")), "_", 2)
+	first, last := -1, -1
+	for i, u := range intervalUnits {
+		if u.name == units[0] {
+			first = i
+		}
+		if u.name == units[len(units)-1] {
+			last = i
+		}
+	}
+	if first < 0 || last < first {
+		return 0, errInvalidEventInterval
+	}
+
+	parts := strings.FieldsFunc(strings.Trim(value, " '\""), func(r rune) bool {
+		return r < '0' || r > '9'
+	})
+	if len(parts) == 0 {
+		return 0, errInvalidEventInterval
+	}
+	// WEEK and QUARTER are not parts of any compound field
+	var chain []int
+	for i := first; i <= last; i++ {
+		if n := intervalUnits[i].name; i == first || i == last || (n != "WEEK" && n != "QUARTER") {
+			chain = append(chain, i)
+		}
+	}
+	if len(parts) > len(chain) {
+		return 0, errInvalidEventInterval
+	}
+	var d time.Duration
+	chain = chain[len(chain)-len(parts):]
+	for i, p := range parts {
+		n, err := strconv.ParseInt(p, 10, 64)
+		if err != nil {
+			return 0, errInvalidEventInterval
+		}
+		d += time.Duration(n) * intervalUnits[chain[i]].duration
+	}
+	return d, nil
+}
@@ -0,0 +1,56 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_EventScheduleSQL(t *testing.T) {
+	at := time.Date(2018, 10, 11, 12, 0, 0, 0, time.Local)
+	cases := []struct {
+		schedule EventSchedule
+		expect   string
+	}{
+		{EventSchedule{At: at}, " ON SCHEDULE AT '2018-10-11 12:00:00'"},
+		{EventSchedule{Interval: "1", Field: "hour"}, " ON SCHEDULE EVERY 1 HOUR"},
+		{EventSchedule{Interval: "1:30", Field: "MINUTE_SECOND", Starts: at, Ends: at.AddDate(0, 1, 0)},
+			" ON SCHEDULE EVERY '1:30' MINUTE_SECOND STARTS '2018-10-11 12:00:00' ENDS '2018-11-11 12:00:00'"},
+	}
+	for _, c := range cases {
+		s, err := c.schedule.sql()
+		if err != nil || s != c.expect {
+			t.Errorf("expect %q, got %q, %v", c.expect, s, err)
+		}
+	}
+	if _, err := (&EventSchedule{Interval: "1"}).sql(); err != errInvalidEventSchedule {
+		t.Error(err)
+	}
+	if s, _ := eventStatusSQL(EventStatusSlavesideDisable); s != " DISABLE ON SLAVE" {
+		t.Error(s)
+	}
+}
+
+func Test_intervalDuration(t *testing.T) {
+	cases := []struct {
+		value, field string
+		expect       time.Duration
+	}{
+		{"1", "HOUR", time.Hour},
+		{"30", "SECOND", 30 * time.Second},
+		{"2", "WEEK", 14 * 24 * time.Hour},
+		{"'1:30'", "MINUTE_SECOND", 90 * time.Second},
+		{"1 12", "DAY_HOUR", 36 * time.Hour},
+		{"2:00", "DAY_MINUTE", 2 * time.Hour},
+		{"1 02:03:04", "DAY_SECOND", 26*time.Hour + 3*time.Minute + 4*time.Second},
+		{"1-6", "YEAR_MONTH", 365*24*time.Hour + 180*24*time.Hour},
+	}
+	for _, c := range cases {
+		d, err := intervalDuration(c.value, c.field)
+		if err != nil || d != c.expect {
+			t.Errorf("%s %s: expect %v, got %v, %v", c.value, c.field, c.expect, d, err)
+		}
+	}
+	if _, err := intervalDuration("1", "FORTNIGHT"); err != errInvalidEventInterval {
+		t.Error(err)
+	}
+}
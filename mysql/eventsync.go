@@ -0,0 +1,154 @@
+package mysql
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// Event is an event defined in code and synchronized to the server by SyncEvents.
+// Status is EventStatusEnable when empty.
+type Event struct {
+	Name     string
+	Schedule EventSchedule
+	Body     string
+	EventOptions
+}
+
+// EventSyncReport is the names of the events changed by SyncEvents
+type EventSyncReport struct {
+	Created []string
+	Altered []string
+	Dropped []string
+}
+
+// Changed report whether SyncEvents changed anything
+func (r *EventSyncReport) Changed() bool {
+	return len(r.Created)+len(r.Altered)+len(r.Dropped) > 0
+}
+
+// SyncEvents make the events in database match events: missing events are created,
+// events differing from INFORMATION_SCHEMA.EVENTS are altered, and events not in param events are
+// dropped if dropUnmanaged is true. Using current database when param database is empty.
+// Note a one-time event without Preserve is dropped by the server after it runs and is created again on next sync.
+func SyncEvents(db *sql.DB, database string, events []Event, dropUnmanaged bool) (*EventSyncReport, error) {
+	database = strings.Trim(database, " ")
+	var err error
+	if database == "" {
+		database, err = getDatabaseName(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var current []*DescEvent
+	current, err = ListEvents(db, database, "")
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]*DescEvent, len(current))
+	for _, de := range current {
+		existing[strings.ToLower(de.Name)] = de
+	}
+
+	report := &EventSyncReport{}
+	managed := make(map[string]bool, len(events))
+	for i := range events {
+		e := &events[i]
+		name := strings.Trim(e.Name, " ")
+		if name == "" {
+			return report, errEmptyParamEvent
+		}
+		managed[strings.ToLower(name)] = true
+		schema := database + "." + name
+
+		de, ok := existing[strings.ToLower(name)]
+		if !ok {
+			if err = CreateEventIfNotExist(db, database, name, e.Schedule, e.Body, &e.EventOptions); err != nil {
+				return report, err
+			}
+			report.Created = append(report.Created, name)
+			continue
+		}
+		if !e.changed(de) {
+			continue
+		}
+		var clauses string
+		if clauses, err = eventClauses(e.Schedule, e.Body, &e.EventOptions, true); err != nil {
+			return report, err
+		}
+		if _, err = db.Exec("ALTER EVENT " + schema + clauses); err != nil {
+			return report, err
+		}
+		report.Altered = append(report.Altered, name)
+	}
+
+	if dropUnmanaged {
+		for _, de := range current {
+			if managed[strings.ToLower(de.Name)] {
+				continue
+			}
+			if err = DropEventIfExist(db, database, de.Name); err != nil {
+				return report, err
+			}
+			report.Dropped = append(report.Dropped, de.Name)
+		}
+	}
+	return report, nil
+}
+
+// changed report whether the event on the server differs from e
+func (e *Event) changed(de *DescEvent) bool {
+	if normalizeSQL(e.Body) != normalizeSQL(de.Definition) {
+		return true
+	}
+	if e.Comment != de.Comment {
+		return true
+	}
+	onCompletion := "NOT PRESERVE"
+	if e.Preserve {
+		onCompletion = "PRESERVE"
+	}
+	if onCompletion != de.OnCompletion {
+		return true
+	}
+	status := EventStatusEnabled
+	switch strings.ToUpper(strings.Trim(e.Status, " ")) {
+	case EventStatusDisable:
+		status = EventStatusDisabled
+	case EventStatusSlavesideDisable:
+		status = EventStatusSlavesideDisable
+	}
+	if status != de.Status {
+		return true
+	}
+
+	s := &e.Schedule
+	if !s.At.IsZero() {
+		return de.Type != "ONE TIME" || !sameWallClock(s.At, de.ExecuteAt)
+	}
+	if de.Type != "RECURRING" {
+		return true
+	}
+	if strings.Trim(s.Interval, " '\"") != strings.Trim(de.IntervalValue.String, " '\"") ||
+		!strings.EqualFold(strings.Trim(s.Field, " "), de.IntervalField.String) {
+		return true
+	}
+	// STARTS is filled with the creation time by the server when it is not given
+	if !s.Starts.IsZero() && !sameWallClock(s.Starts, de.Starts) {
+		return true
+	}
+	if s.Ends.IsZero() {
+		return de.Ends != nil
+	}
+	return !sameWallClock(s.Ends, de.Ends)
+}
+
+// sameWallClock report whether t and the time read from the server show the same date and time
+func sameWallClock(t time.Time, server *time.Time) bool {
+	return server != nil && t.Format("2006-01-02 15:04:05") == server.Format("2006-01-02 15:04:05")
+}
+
+// normalizeSQL collapse white spaces and remove the trailing semicolon of a statement
+func normalizeSQL(s string) string {
+	return strings.TrimRight(strings.Join(strings.Fields(s), " "), "; ")
+}
@@ -0,0 +1,56 @@
+package mysql
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_EventChanged(t *testing.T) {
+	starts := time.Date(2018, 10, 11, 0, 0, 0, 0, time.UTC)
+	e := Event{
+		Name:     "purge_log",
+		Schedule: EventSchedule{Interval: "1", Field: "day", Starts: starts},
+		Body:     "DELETE FROM log\n\tWHERE created_at < NOW() - INTERVAL 7 DAY;",
+	}
+	de := &DescEvent{
+		Name:          "purge_log",
+		Definition:    "DELETE FROM log WHERE created_at < NOW() - INTERVAL 7 DAY",
+		Type:          "RECURRING",
+		IntervalValue: &sql.NullString{String: "1", Valid: true},
+		IntervalField: &sql.NullString{String: "DAY", Valid: true},
+		Starts:        &starts,
+		Status:        EventStatusEnabled,
+		OnCompletion:  "NOT PRESERVE",
+	}
+	if e.changed(de) {
+		t.Error("expect unchanged")
+	}
+
+	e.Status = EventStatusDisable
+	if !e.changed(de) {
+		t.Error("expect status changed")
+	}
+	// a disabled event without status is altered back to enabled
+	e.Status = ""
+	de.Status = EventStatusDisabled
+	if !e.changed(de) {
+		t.Error("expect status changed")
+	}
+	clauses, err := eventClauses(e.Schedule, e.Body, &e.EventOptions, true)
+	if err != nil || !strings.Contains(clauses, " ENABLE COMMENT ") {
+		t.Error(clauses, err)
+	}
+	de.Status = EventStatusEnabled
+
+	e.Schedule.Interval = "2"
+	if !e.changed(de) {
+		t.Error("expect schedule changed")
+	}
+	e.Schedule.Interval = "1"
+	e.Comment = "purge"
+	if !e.changed(de) {
+		t.Error("expect comment changed")
+	}
+}
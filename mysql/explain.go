@@ -0,0 +1,179 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ExplainRow is a row of the tabular EXPLAIN output
+type ExplainRow struct {
+	ID           int64
+	SelectType   string
+	Table        string // the alias of the table if the query gives one
+	Partitions   string
+	Type         string // access type: system, const, eq_ref, ref, range, index, ALL...
+	PossibleKeys []string
+	Key          string // comma separated for index_merge
+	KeyLen       string
+	Ref          string
+	Rows         int64
+	Filtered     float64
+	Extra        string
+}
+
+// ExplainWarning is a row of SHOW WARNINGS after EXPLAIN, Note 1003 holds the query rewritten by the optimizer
+type ExplainWarning struct {
+	Level   string
+	Code    int
+	Message string
+}
+
+// Plan is the execution plan of a query
+type Plan struct {
+	Rows     []ExplainRow
+	JSON     map[string]interface{} // the parsed EXPLAIN FORMAT=JSON, nil on servers older than 5.6.5
+	Warnings []ExplainWarning
+
+	FullTableScan  bool // a table is read with type ALL
+	Filesort       bool // Using filesort
+	TemporaryTable bool // Using temporary
+	NoIndex        bool // a table is read without any index
+}
+
+// Explain explain query with args on a pinned connection, so SHOW WARNINGS reads the warnings of the EXPLAIN.
+func Explain(db *sql.DB, query string, args ...interface{}) (*Plan, error) {
+	if query = strings.Trim(query, " "); query == "" {
+		return nil, errEmptyParamQuery
+	}
+	v, err := getServerVersion(db)
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	p := &Plan{}
+	if v.atLeast(5, 6, 5) {
+		var tree string
+		if err = conn.QueryRowContext(ctx, "EXPLAIN FORMAT=JSON "+query, args...).Scan(&tree); err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal([]byte(tree), &p.JSON); err != nil {
+			return nil, err
+		}
+	}
+
+	rows, err := conn.QueryContext(ctx, "EXPLAIN "+query, args...)
+	if err != nil {
+		return nil, err
+	}
+	sets, err := readResultSets(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+	for _, set := range sets {
+		for _, values := range set.Rows {
+			p.Rows = append(p.Rows, explainRow(set.Columns, values))
+		}
+	}
+
+	rows, err = conn.QueryContext(ctx, "SHOW WARNINGS")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var w ExplainWarning
+		if err = rows.Scan(&w.Level, &w.Code, &w.Message); err != nil {
+			return nil, err
+		}
+		p.Warnings = append(p.Warnings, w)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	p.analyze()
+	return p, nil
+}
+
+// explainRow build an ExplainRow from the columns of EXPLAIN, which vary with the server version
+func explainRow(columns []string, values []interface{}) ExplainRow {
+	var r ExplainRow
+	for i, column := range columns {
+		if values[i] == nil {
+			continue
+		}
+		value := fmt.Sprint(values[i])
+		switch strings.ToLower(column) {
+		case "id":
+			r.ID, _ = strconv.ParseInt(value, 10, 64)
+		case "select_type":
+			r.SelectType = value
+		case "table":
+			r.Table = value
+		case "partitions":
+			r.Partitions = value
+		case "type":
+			r.Type = value
+		case "possible_keys":
+			r.PossibleKeys = strings.Split(value, ",")
+		case "key":
+			r.Key = value
+		case "key_len":
+			r.KeyLen = value
+		case "ref":
+			r.Ref = value
+		case "rows":
+			r.Rows, _ = strconv.ParseInt(value, 10, 64)
+		case "filtered":
+			r.Filtered, _ = strconv.ParseFloat(value, 64)
+		case "extra":
+			r.Extra = value
+		}
+	}
+	return r
+}
+
+// analyze set the flags of the plan from its rows
+func (p *Plan) analyze() {
+	for _, r := range p.Rows {
+		if r.Type == "ALL" {
+			p.FullTableScan = true
+		}
+		if strings.Contains(r.Extra, "Using filesort") {
+			p.Filesort = true
+		}
+		if strings.Contains(r.Extra, "Using temporary") {
+			p.TemporaryTable = true
+		}
+		// <derivedN>, <unionM,N> and <subqueryN> are materialized by the query itself, system tables have a single row
+		if r.Table != "" && !strings.HasPrefix(r.Table, "<") && r.Key == "" && r.Type != "system" {
+			p.NoIndex = true
+		}
+	}
+}
+
+// UsesIndex report whether the plan reads table, or the alias given in the query, with index
+func (p *Plan) UsesIndex(table, index string) bool {
+	for _, r := range p.Rows {
+		if r.Table != table {
+			continue
+		}
+		for _, key := range strings.Split(r.Key, ",") {
+			if key == index {
+				return true
+			}
+		}
+	}
+	return false
+}
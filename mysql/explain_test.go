@@ -0,0 +1,33 @@
+package mysql
+
+import (
+	"testing"
+)
+
+func Test_explainRow(t *testing.T) {
+	columns := []string{"id", "select_type", "table", "partitions", "type", "possible_keys", "key", "key_len", "ref", "rows", "filtered", "Extra"}
+	r := explainRow(columns, []interface{}{"1", "SIMPLE", "u", nil, "ref", "idx_name,idx_name_age", "idx_name", "258", "const", "3", "100.00", "Using index condition"})
+	if r.ID != 1 || r.Table != "u" || r.Type != "ref" || len(r.PossibleKeys) != 2 || r.Key != "idx_name" || r.Rows != 3 || r.Filtered != 100 || r.Extra != "Using index condition" {
+		t.Error(r)
+	}
+}
+
+func Test_PlanAnalyze(t *testing.T) {
+	p := &Plan{Rows: []ExplainRow{
+		{ID: 1, Table: "u", Type: "index_merge", Key: "idx_name,idx_age"},
+		{ID: 1, Table: "<derived2>", Type: "ALL", Extra: "Using temporary; Using filesort"},
+	}}
+	p.analyze()
+	if !p.FullTableScan || !p.Filesort || !p.TemporaryTable || p.NoIndex {
+		t.Error(p)
+	}
+	if !p.UsesIndex("u", "idx_age") || p.UsesIndex("u", "idx") || p.UsesIndex("o", "idx_age") {
+		t.Error("UsesIndex")
+	}
+
+	p = &Plan{Rows: []ExplainRow{{ID: 1, Table: "o", Type: "ALL", Extra: "Using where"}}}
+	p.analyze()
+	if !p.NoIndex || p.Filesort || p.TemporaryTable {
+		t.Error(p)
+	}
+}
@@ -0,0 +1,151 @@
+package mysql
+
+import (
+	"database/sql"
+	"sort"
+	"strings"
+)
+
+// DropPolicy guards the destructive operations DropDatabaseIfExist, DropTable, DropTableIfExist
+// and DropColumnIfExist. System databases are always refused.
+type DropPolicy struct {
+	// AllowCurrentDatabase let DropDatabaseIfExist drop the current database when param database is empty.
+	AllowCurrentDatabase bool
+	// OnlyIfEmpty refuse to drop a database containing tables, or a table or column of a table containing rows.
+	OnlyIfEmpty bool
+	// MaxTables refuse to drop a database with more tables, 0 means no limit.
+	MaxTables int
+	// MaxRows refuse to drop when the estimated number of rows (TABLE_ROWS) is larger, 0 means no limit.
+	MaxRows int64
+	// RequireConfirm refuse to drop unless Confirm is the name of the dropped object,
+	// that is database, database.table or database.table.column.
+	RequireConfirm bool
+	Confirm        string
+	// DryRun receives the statement instead of executing it when it is not nil.
+	DryRun func(query string)
+	// RecycleBin is the trash database, DropTable and DropTableIfExist rename the table into it
+	// instead of dropping it when it is not empty. See ListDropped, RestoreTable and PurgeExpired.
+	RecycleBin string
+}
+
+// DefaultDropPolicy is used by DropDatabaseIfExist, DropTable, DropTableIfExist and DropColumnIfExist.
+var DefaultDropPolicy = DropPolicy{}
+
+// systemDatabases can never be dropped, nor anything in them
+var systemDatabases = map[string]bool{
+	"mysql":              true,
+	"information_schema": true,
+	"performance_schema": true,
+	"sys":                true,
+}
+
+// isSystemDatabase report whether database is one of the system databases
+func isSystemDatabase(database string) bool {
+	return systemDatabases[strings.ToLower(database)]
+}
+
+// systemDatabasesSQL render systemDatabases as the list of a NOT IN condition, sorted to keep queries stable
+func systemDatabasesSQL() string {
+	databases := make([]string, 0, len(systemDatabases))
+	for database := range systemDatabases {
+		databases = append(databases, quoteString(database))
+	}
+	sort.Strings(databases)
+	return "(" + strings.Join(databases, ", ") + ")"
+}
+
+// checkDatabase check whether database can be dropped
+func (p *DropPolicy) checkDatabase(db *sql.DB, database string) error {
+	if isSystemDatabase(database) {
+		return errDropSystemDatabase
+	}
+	if err := p.confirm(database); err != nil {
+		return err
+	}
+	if !p.OnlyIfEmpty && p.MaxTables <= 0 && p.MaxRows <= 0 {
+		return nil
+	}
+	var (
+		tables int
+		rows   int64
+	)
+	err := db.QueryRow(
+		`SELECT COUNT(0), COALESCE(SUM(TABLE_ROWS), 0) 
+			FROM information_schema.TABLES 
+			WHERE TABLE_SCHEMA = ?`, database,
+	).Scan(&tables, &rows)
+	if err != nil {
+		return err
+	}
+	if p.OnlyIfEmpty && tables > 0 {
+		return errDropNotEmpty
+	}
+	if p.MaxTables > 0 && tables > p.MaxTables {
+		return errDropTooManyTables
+	}
+	if p.MaxRows > 0 && rows > p.MaxRows {
+		return errDropTooManyRows
+	}
+	return nil
+}
+
+// checkTable check whether database.table, or the column of it named by target, can be dropped
+func (p *DropPolicy) checkTable(db *sql.DB, database, table, target string) error {
+	if isSystemDatabase(database) {
+		return errDropSystemDatabase
+	}
+	if err := p.confirm(target); err != nil {
+		return err
+	}
+	if p.OnlyIfEmpty {
+		notEmpty, err := exist(db.QueryRow("SELECT 1 FROM " + database + "." + table + " LIMIT 1"))
+		if err != nil {
+			return err
+		}
+		if notEmpty {
+			return errDropNotEmpty
+		}
+	}
+	if p.MaxRows > 0 {
+		var rows sql.NullInt64
+		err := db.QueryRow(
+			`SELECT TABLE_ROWS 
+				FROM information_schema.TABLES 
+				WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?`, database, table,
+		).Scan(&rows)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		if rows.Int64 > p.MaxRows {
+			return errDropTooManyRows
+		}
+	}
+	return nil
+}
+
+// confirm check the confirm token against the name of the dropped object
+func (p *DropPolicy) confirm(target string) error {
+	if p.RequireConfirm && strings.Trim(p.Confirm, " ") != target {
+		return errDropNotConfirmed
+	}
+	return nil
+}
+
+// execDDL execute an ALTER TABLE on database.table through DefaultDDLGuard, or pass it to DryRun
+func (p *DropPolicy) execDDL(db *sql.DB, database, table, query string) error {
+	if p.DryRun != nil {
+		p.DryRun(query)
+		return nil
+	}
+	return execDDL(db, database, table, query)
+}
+
+// exec execute query, or pass it to DryRun
+func (p *DropPolicy) exec(db *sql.DB, query string) error {
+	if p.DryRun != nil {
+		p.DryRun(query)
+		return nil
+	}
+	_, err := db.Exec(query)
+	return err
+}
@@ -0,0 +1,39 @@
+package mysql
+
+import (
+	"testing"
+)
+
+func Test_DropPolicy(t *testing.T) {
+	p := &DropPolicy{}
+	for _, database := range []string{"mysql", "INFORMATION_SCHEMA", "performance_schema", "sys"} {
+		if err := p.checkDatabase(nil, database); err != errDropSystemDatabase {
+			t.Error(database, err)
+		}
+		if err := p.checkTable(nil, database, "user", database+".user"); err != errDropSystemDatabase {
+			t.Error(database, err)
+		}
+	}
+	if s := systemDatabasesSQL(); s != "('information_schema', 'mysql', 'performance_schema', 'sys')" {
+		t.Error(s)
+	}
+	if err := DropDatabaseIfExistWithPolicy(nil, " ", p); err != errEmptyParamDatabase {
+		t.Error(err)
+	}
+
+	var planned []string
+	p = &DropPolicy{
+		RequireConfirm: true,
+		Confirm:        "mydb.user.name",
+		DryRun:         func(query string) { planned = append(planned, query) },
+	}
+	if err := p.checkTable(nil, "mydb", "user", "mydb.user"); err != errDropNotConfirmed {
+		t.Error(err)
+	}
+	if err := p.checkTable(nil, "mydb", "user", "mydb.user.name"); err != nil {
+		t.Error(err)
+	}
+	if err := p.exec(nil, "ALTER TABLE mydb.user DROP COLUMN name"); err != nil || len(planned) != 1 {
+		t.Error(err, planned)
+	}
+}
@@ -74,8 +74,7 @@ func CreateIndexIfNotExist(db *sql.DB, schema, index string, columns []string, u
 		fulltextStr = " FULLTEXT"
 	}
 	query := "CREATE" + uniqueStr + fulltextStr + " INDEX " + index + " ON " + schema + "(" + strings.Join(columns, ",") + ")"
-	_, err = db.Exec(query)
-	return err
+	return execDDL(db, database, table, query)
 }
 
 // func DropIndex(db *sql.DB, schema, index string) error {
@@ -109,6 +108,5 @@ func DropIndexIfExist(db *sql.DB, schema, index string) error {
 	if !isexist {
 		return nil
 	}
-	_, err = db.Exec("DROP INDEX " + index + " ON " + schema)
-	return err
+	return execDDL(db, database, table, "DROP INDEX "+index+" ON "+schema)
 }
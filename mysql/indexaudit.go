@@ -0,0 +1,211 @@
+package mysql
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+)
+
+// IndexSuggestion is an index which can probably be dropped
+type IndexSuggestion struct {
+	Schema string
+	Table  string
+	Index  string
+	Reason string
+	Call   string // the suggested call, e.g. DropIndexIfExist(db, "mydb.user", "idx_name")
+}
+
+// tableIndex is an index read from information_schema.STATISTICS
+type tableIndex struct {
+	schema  string
+	table   string
+	name    string
+	unique  bool
+	typ     string   // BTREE | HASH | FULLTEXT | SPATIAL
+	columns []string // column(sub_part) in the order of the index
+}
+
+// AuditIndexes find the redundant and unused indexes of the tables in databases,
+// or in all databases except the system ones if none is given.
+func AuditIndexes(db *sql.DB, databases ...string) ([]IndexSuggestion, error) {
+	redundant, err := RedundantIndexes(db, databases...)
+	if err != nil {
+		return nil, err
+	}
+	unused, err := UnusedIndexes(db, databases...)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(redundant))
+	for _, s := range redundant {
+		seen[s.Schema+"."+s.Table+"."+s.Index] = true
+	}
+	for _, s := range unused {
+		if !seen[s.Schema+"."+s.Table+"."+s.Index] {
+			redundant = append(redundant, s)
+		}
+	}
+	return redundant, nil
+}
+
+// RedundantIndexes find indexes which duplicate another index or are its left prefix,
+// including duplicates of the primary key, from information_schema.STATISTICS.
+func RedundantIndexes(db *sql.DB, databases ...string) ([]IndexSuggestion, error) {
+	where, args := auditDatabases("TABLE_SCHEMA", databases)
+	rows, err := db.Query(
+		`SELECT TABLE_SCHEMA, TABLE_NAME, INDEX_NAME, NON_UNIQUE, INDEX_TYPE, COLUMN_NAME, SUB_PART 
+			FROM information_schema.STATISTICS 
+			WHERE `+where+` 
+			ORDER BY TABLE_SCHEMA, TABLE_NAME, INDEX_NAME, SEQ_IN_INDEX`, args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		suggestions []IndexSuggestion
+		indexes     []*tableIndex
+	)
+	for rows.Next() {
+		var (
+			index     tableIndex
+			nonUnique int
+			column    sql.NullString // NULL for functional key parts on 8.0
+			subPart   sql.NullInt64
+		)
+		if err = rows.Scan(&index.schema, &index.table, &index.name, &nonUnique, &index.typ, &column, &subPart); err != nil {
+			return nil, err
+		}
+		if column.String != "" && subPart.Valid {
+			column.String += "(" + strconv.FormatInt(subPart.Int64, 10) + ")"
+		}
+		n := len(indexes)
+		if n > 0 && indexes[n-1].schema == index.schema && indexes[n-1].table == index.table && indexes[n-1].name == index.name {
+			indexes[n-1].columns = append(indexes[n-1].columns, column.String)
+			continue
+		}
+		if n > 0 && (indexes[n-1].schema != index.schema || indexes[n-1].table != index.table) {
+			suggestions = append(suggestions, redundantIndexes(indexes)...)
+			indexes = nil
+		}
+		index.unique = nonUnique == 0
+		index.columns = []string{column.String}
+		indexes = append(indexes, &index)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return append(suggestions, redundantIndexes(indexes)...), nil
+}
+
+// redundantIndexes find the redundant ones among the indexes of a table
+func redundantIndexes(indexes []*tableIndex) []IndexSuggestion {
+	var suggestions []IndexSuggestion
+	for _, a := range indexes {
+		if a.name == "PRIMARY" {
+			continue
+		}
+		for _, b := range indexes {
+			if a == b || !covers(b, a) {
+				continue
+			}
+			var reason string
+			same := len(a.columns) == len(b.columns)
+			switch {
+			case same && b.name == "PRIMARY":
+				reason = "duplicate of the primary key"
+			case same && a.unique == b.unique && a.name < b.name:
+				// of two identical indexes the one sorted first is kept
+				continue
+			case same && a.unique && !b.unique:
+				continue
+			case same:
+				reason = "duplicate of index " + b.name
+			case a.unique:
+				// a unique index enforces a constraint its extensions do not
+				continue
+			case b.name == "PRIMARY":
+				reason = "left prefix of the primary key"
+			default:
+				reason = "left prefix of index " + b.name
+			}
+			reason += " (" + strings.Join(a.columns, ", ") + ")"
+			suggestions = append(suggestions, indexSuggestion(a.schema, a.table, a.name, reason))
+			break
+		}
+	}
+	return suggestions
+}
+
+// covers report whether b makes a useless: both are of the same type, and the columns of a are a left prefix of those of b.
+// Only BTREE indexes can be used by their left prefix.
+func covers(b, a *tableIndex) bool {
+	if a.typ != b.typ || len(a.columns) > len(b.columns) {
+		return false
+	}
+	if len(a.columns) < len(b.columns) && a.typ != "BTREE" {
+		return false
+	}
+	for i, column := range a.columns {
+		if column == "" || column != b.columns[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// UnusedIndexes find non-unique indexes never used since the server started, from
+// performance_schema.table_io_waits_summary_by_index_usage, which is what sys.schema_unused_indexes is based on.
+// The counters are reset when the server restarts, so the result is meaningful only after a representative uptime.
+func UnusedIndexes(db *sql.DB, databases ...string) ([]IndexSuggestion, error) {
+	where, args := auditDatabases("u.OBJECT_SCHEMA", databases)
+	rows, err := db.Query(
+		`SELECT u.OBJECT_SCHEMA, u.OBJECT_NAME, u.INDEX_NAME 
+			FROM performance_schema.table_io_waits_summary_by_index_usage u 
+			JOIN information_schema.STATISTICS s ON s.TABLE_SCHEMA = u.OBJECT_SCHEMA AND s.TABLE_NAME = u.OBJECT_NAME 
+				AND s.INDEX_NAME = u.INDEX_NAME AND s.SEQ_IN_INDEX = 1 
+			WHERE u.INDEX_NAME IS NOT NULL AND u.INDEX_NAME <> 'PRIMARY' AND u.COUNT_STAR = 0 AND s.NON_UNIQUE = 1 
+				AND `+where+` 
+			ORDER BY u.OBJECT_SCHEMA, u.OBJECT_NAME, u.INDEX_NAME`, args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var suggestions []IndexSuggestion
+	for rows.Next() {
+		var schema, table, index string
+		if err = rows.Scan(&schema, &table, &index); err != nil {
+			return nil, err
+		}
+		suggestions = append(suggestions, indexSuggestion(schema, table, index, "not used since the server started"))
+	}
+	return suggestions, rows.Err()
+}
+
+// indexSuggestion build a suggestion of dropping schema.table.index
+func indexSuggestion(schema, table, index, reason string) IndexSuggestion {
+	return IndexSuggestion{
+		Schema: schema,
+		Table:  table,
+		Index:  index,
+		Reason: reason,
+		Call:   "DropIndexIfExist(db, " + strconv.Quote(schema+"."+table) + ", " + strconv.Quote(index) + ")",
+	}
+}
+
+// auditDatabases build the condition on column selecting databases, or all databases except the system ones
+func auditDatabases(column string, databases []string) (string, []interface{}) {
+	var args []interface{}
+	for _, database := range databases {
+		if database = strings.Trim(database, " "); database != "" {
+			args = append(args, database)
+		}
+	}
+	if len(args) > 0 {
+		return column + " IN (?" + strings.Repeat(", ?", len(args)-1) + ")", args
+	}
+	return column + " NOT IN " + systemDatabasesSQL(), nil
+}
@@ -0,0 +1,40 @@
+package mysql
+
+import (
+	"testing"
+)
+
+func Test_redundantIndexes(t *testing.T) {
+	index := func(name string, unique bool, columns ...string) *tableIndex {
+		return &tableIndex{schema: "mydb", table: "user", name: name, unique: unique, typ: "BTREE", columns: columns}
+	}
+	suggestions := redundantIndexes([]*tableIndex{
+		index("PRIMARY", true, "id"),
+		index("idx_id", false, "id"),
+		index("idx_name", false, "name"),
+		index("idx_name_age", false, "name", "age"),
+		index("uniq_email", true, "email"),
+		index("idx_email", false, "email"),
+		index("uniq_email_name", true, "email", "name"),
+		index("idx_a", false, "age"),
+		index("idx_b", false, "age"),
+		{schema: "mydb", table: "user", name: "ft_name", typ: "FULLTEXT", columns: []string{"name"}},
+	})
+	expect := map[string]string{
+		"idx_id":    "duplicate of the primary key (id)",
+		"idx_name":  "left prefix of index idx_name_age (name)",
+		"idx_email": "duplicate of index uniq_email (email)",
+		"idx_b":     "duplicate of index idx_a (age)",
+	}
+	if len(suggestions) != len(expect) {
+		t.Fatal(suggestions)
+	}
+	for _, s := range suggestions {
+		if expect[s.Index] != s.Reason {
+			t.Errorf("%s: expect %q, got %q", s.Index, expect[s.Index], s.Reason)
+		}
+	}
+	if s := suggestions[0]; s.Call != `DropIndexIfExist(db, "mydb.user", "idx_id")` {
+		t.Error(s.Call)
+	}
+}
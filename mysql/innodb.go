@@ -0,0 +1,384 @@
+package mysql
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Titles of the sections of SHOW ENGINE INNODB STATUS used by ParseInnoDBStatus
+const (
+	InnoDBSectionSemaphores    = "SEMAPHORES"
+	InnoDBSectionDeadlock      = "LATEST DETECTED DEADLOCK"
+	InnoDBSectionBufferPool    = "BUFFER POOL AND MEMORY"
+	InnoDBSectionRowOperations = "ROW OPERATIONS"
+	InnoDBSectionTransactions  = "TRANSACTIONS"
+)
+
+// InnoDBStatus is the parsed output of SHOW ENGINE INNODB STATUS
+type InnoDBStatus struct {
+	Sections       map[string]string // text of each section by title
+	LatestDeadlock *Deadlock         // nil if no deadlock was detected since the server started
+	BufferPool     BufferPoolStats
+	RowOperations  RowOperationStats
+	Semaphores     SemaphoreStats
+}
+
+// Deadlock is the latest detected deadlock
+type Deadlock struct {
+	Time         time.Time // the server time, parsed in UTC
+	Transactions []DeadlockTransaction
+	RolledBack   int    // Number of the transaction rolled back, 0 if unknown
+	Text         string // the whole section
+}
+
+// DeadlockTransaction is a transaction involved in a deadlock
+type DeadlockTransaction struct {
+	Number     int // 1, 2 as numbered in the output
+	TrxID      string
+	Thread     uint64 // MySQL thread id
+	Status     string // the TRANSACTION line, e.g. TRANSACTION 2345, ACTIVE 10 sec starting index read
+	Query      string
+	HoldsLocks []string // RECORD LOCKS or TABLE LOCK lines
+	WaitsLocks []string // RECORD LOCKS or TABLE LOCK lines
+}
+
+// BufferPoolStats is parsed from BUFFER POOL AND MEMORY, sizes are in pages
+type BufferPoolStats struct {
+	TotalMemory   uint64 // Total large memory allocated, in bytes
+	Size          uint64
+	Free          uint64
+	DatabasePages uint64
+	ModifiedPages uint64
+	HitRate       float64 // hits per access in the last interval, 0 if there was no access
+}
+
+// RowOperationStats is parsed from ROW OPERATIONS
+type RowOperationStats struct {
+	Inserted      uint64
+	Updated       uint64
+	Deleted       uint64
+	Read          uint64
+	InsertsPerSec float64
+	UpdatesPerSec float64
+	DeletesPerSec float64
+	ReadsPerSec   float64
+}
+
+// SemaphoreStats is parsed from SEMAPHORES
+type SemaphoreStats struct {
+	ReservationCount uint64
+	SignalCount      uint64
+	OSWaits          uint64   // sum of OS waits of all kinds of rw-locks
+	LongWaits        []string // lines about threads waiting for a semaphore
+}
+
+// GetInnoDBStatus run SHOW ENGINE INNODB STATUS and parse the output
+func GetInnoDBStatus(db *sql.DB) (*InnoDBStatus, error) {
+	var typ, name, status string
+	if err := db.QueryRow("SHOW ENGINE INNODB STATUS").Scan(&typ, &name, &status); err != nil {
+		return nil, err
+	}
+	return ParseInnoDBStatus(status), nil
+}
+
+// ParseInnoDBStatus parse the output of SHOW ENGINE INNODB STATUS
+func ParseInnoDBStatus(status string) *InnoDBStatus {
+	s := &InnoDBStatus{Sections: innodbSections(status)}
+	if text, ok := s.Sections[InnoDBSectionDeadlock]; ok {
+		s.LatestDeadlock = parseDeadlock(text)
+	}
+	s.BufferPool = parseBufferPool(s.Sections[InnoDBSectionBufferPool])
+	s.RowOperations = parseRowOperations(s.Sections[InnoDBSectionRowOperations])
+	s.Semaphores = parseSemaphores(s.Sections[InnoDBSectionSemaphores])
+	return s
+}
+
+// innodbSections split the output into sections, a title is a line between two lines of dashes
+func innodbSections(status string) map[string]string {
+	sections := make(map[string]string)
+	lines := strings.Split(status, "\n")
+	var (
+		title string
+		body  []string
+	)
+	for i := 0; i < len(lines); i++ {
+		if i+2 < len(lines) && isRule(lines[i], '-') && isRule(lines[i+2], '-') && !isRule(lines[i+1], '-') {
+			if title != "" {
+				sections[title] = strings.Trim(strings.Join(body, "\n"), "\n")
+			}
+			title, body = strings.TrimSpace(lines[i+1]), nil
+			i += 2
+			continue
+		}
+		if strings.HasPrefix(strings.TrimSpace(lines[i]), "END OF INNODB MONITOR OUTPUT") {
+			// the rule above the end line is not part of the last section
+			if n := len(body); n > 0 && isRule(body[n-1], '-') {
+				body = body[:n-1]
+			}
+			break
+		}
+		body = append(body, strings.TrimRight(lines[i], "\r"))
+	}
+	if title != "" {
+		sections[title] = strings.Trim(strings.Join(body, "\n"), "\n")
+	}
+	return sections
+}
+
+// isRule report whether line only consists of c
+func isRule(line string, c rune) bool {
+	line = strings.TrimSpace(line)
+	return line != "" && strings.Trim(line, string(c)) == ""
+}
+
+// parseDeadlock parse the LATEST DETECTED DEADLOCK section
+func parseDeadlock(text string) *Deadlock {
+	d := &Deadlock{Text: text}
+	var (
+		trx  *DeadlockTransaction
+		mode string // trx, holds or waits
+	)
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if i == 0 && len(line) >= 19 {
+			d.Time, _ = time.ParseInLocation("2006-01-02 15:04:05", line[:19], time.UTC)
+			continue
+		}
+		if strings.HasPrefix(line, "*** ") {
+			n, rest := deadlockMarker(line)
+			switch {
+			case strings.HasPrefix(rest, "TRANSACTION:"):
+				d.Transactions = append(d.Transactions, DeadlockTransaction{Number: n})
+				trx, mode = &d.Transactions[len(d.Transactions)-1], "trx"
+			case strings.HasPrefix(rest, "HOLDS THE LOCK"):
+				trx, mode = d.transaction(n), "holds"
+			case strings.HasPrefix(rest, "WAITING FOR THIS LOCK"):
+				trx, mode = d.transaction(n), "waits"
+			case strings.HasPrefix(line, "*** WE ROLL BACK TRANSACTION ("):
+				d.RolledBack, _ = strconv.Atoi(strings.TrimRight(strings.TrimPrefix(line, "*** WE ROLL BACK TRANSACTION ("), ")"))
+				trx, mode = nil, ""
+			default:
+				trx, mode = nil, ""
+			}
+			continue
+		}
+		if trx == nil {
+			continue
+		}
+		switch mode {
+		case "trx":
+			switch {
+			case strings.HasPrefix(line, "TRANSACTION "):
+				trx.Status = line
+				trx.TrxID = strings.TrimRight(strings.Fields(line)[1], ",")
+			case strings.HasPrefix(line, "MySQL thread id "):
+				trx.Thread, _ = strconv.ParseUint(strings.TrimRight(strings.Fields(line)[3], ","), 10, 64)
+				// the statement follows the thread line
+				mode = "query"
+			}
+		case "query":
+			if trx.Query != "" {
+				trx.Query += "\n"
+			}
+			trx.Query += line
+		case "holds":
+			if strings.HasPrefix(line, "RECORD LOCKS") || strings.HasPrefix(line, "TABLE LOCK") {
+				trx.HoldsLocks = append(trx.HoldsLocks, line)
+			}
+		case "waits":
+			if strings.HasPrefix(line, "RECORD LOCKS") || strings.HasPrefix(line, "TABLE LOCK") {
+				trx.WaitsLocks = append(trx.WaitsLocks, line)
+			}
+		}
+	}
+	return d
+}
+
+// deadlockMarker parse a line like *** (1) TRANSACTION: into 1 and TRANSACTION:
+func deadlockMarker(line string) (int, string) {
+	line = strings.TrimPrefix(line, "*** ")
+	if !strings.HasPrefix(line, "(") {
+		return 0, line
+	}
+	end := strings.Index(line, ")")
+	if end < 0 {
+		return 0, line
+	}
+	n, _ := strconv.Atoi(line[1:end])
+	return n, strings.TrimSpace(line[end+1:])
+}
+
+// transaction get the transaction numbered n
+func (d *Deadlock) transaction(n int) *DeadlockTransaction {
+	for i := range d.Transactions {
+		if d.Transactions[i].Number == n {
+			return &d.Transactions[i]
+		}
+	}
+	return nil
+}
+
+// parseBufferPool parse the BUFFER POOL AND MEMORY section
+func parseBufferPool(text string) (b BufferPoolStats) {
+	for _, line := range strings.Split(text, "\n") {
+		switch {
+		case strings.HasPrefix(line, "Total large memory allocated"), strings.HasPrefix(line, "Total memory allocated"):
+			b.TotalMemory = lastUint(line)
+		case strings.HasPrefix(line, "Buffer pool size"):
+			b.Size = lastUint(line)
+		case strings.HasPrefix(line, "Free buffers"):
+			b.Free = lastUint(line)
+		case strings.HasPrefix(line, "Database pages"):
+			b.DatabasePages = lastUint(line)
+		case strings.HasPrefix(line, "Modified db pages"):
+			b.ModifiedPages = lastUint(line)
+		case strings.HasPrefix(line, "Buffer pool hit rate"):
+			// Buffer pool hit rate 1000 / 1000, young-making rate 0 / 1000 not 0 / 1000
+			f := strings.Fields(strings.SplitN(line, ",", 2)[0])
+			if len(f) == 7 {
+				hits, _ := strconv.ParseFloat(f[4], 64)
+				total, _ := strconv.ParseFloat(f[6], 64)
+				if total > 0 {
+					b.HitRate = hits / total
+				}
+			}
+		}
+	}
+	return
+}
+
+// parseRowOperations parse the ROW OPERATIONS section
+func parseRowOperations(text string) (r RowOperationStats) {
+	for _, line := range strings.Split(text, "\n") {
+		switch {
+		case strings.HasPrefix(line, "Number of rows inserted"):
+			// Number of rows inserted 10, updated 2, deleted 0, read 100
+			values := commaValues(strings.TrimPrefix(line, "Number of rows "))
+			r.Inserted = uint64(values["inserted"])
+			r.Updated = uint64(values["updated"])
+			r.Deleted = uint64(values["deleted"])
+			r.Read = uint64(values["read"])
+		case strings.Contains(line, "inserts/s"):
+			// 0.00 inserts/s, 0.00 updates/s, 0.00 deletes/s, 0.00 reads/s
+			for _, part := range strings.Split(line, ",") {
+				f := strings.Fields(part)
+				if len(f) != 2 {
+					continue
+				}
+				v, _ := strconv.ParseFloat(f[0], 64)
+				switch f[1] {
+				case "inserts/s":
+					r.InsertsPerSec = v
+				case "updates/s":
+					r.UpdatesPerSec = v
+				case "deletes/s":
+					r.DeletesPerSec = v
+				case "reads/s":
+					r.ReadsPerSec = v
+				}
+			}
+		}
+	}
+	return
+}
+
+// parseSemaphores parse the SEMAPHORES section
+func parseSemaphores(text string) (s SemaphoreStats) {
+	for _, line := range strings.Split(text, "\n") {
+		switch {
+		case strings.HasPrefix(line, "OS WAIT ARRAY INFO: reservation count"):
+			s.ReservationCount = lastUint(line)
+		case strings.HasPrefix(line, "OS WAIT ARRAY INFO: signal count"):
+			s.SignalCount = lastUint(line)
+		case strings.HasPrefix(line, "RW-"):
+			// RW-shared spins 0, rounds 0, OS waits 0
+			for _, part := range strings.Split(line, ",") {
+				if strings.HasPrefix(strings.TrimSpace(part), "OS waits") {
+					s.OSWaits += lastUint(part)
+				}
+			}
+		case strings.HasPrefix(line, "--Thread"):
+			s.LongWaits = append(s.LongWaits, line)
+		}
+	}
+	return
+}
+
+// lastUint parse the last field of line as an unsigned integer, 0 if it is not a number
+func lastUint(line string) uint64 {
+	f := strings.Fields(strings.TrimRight(line, ","))
+	if len(f) == 0 {
+		return 0
+	}
+	n, _ := strconv.ParseUint(strings.TrimRight(f[len(f)-1], ","), 10, 64)
+	return n
+}
+
+// commaValues parse text like "inserted 10, updated 2" into a map
+func commaValues(text string) map[string]float64 {
+	values := make(map[string]float64)
+	for _, part := range strings.Split(text, ",") {
+		f := strings.Fields(part)
+		if len(f) != 2 {
+			continue
+		}
+		values[f[0]], _ = strconv.ParseFloat(f[1], 64)
+	}
+	return values
+}
+
+// defaultDeadlockInterval is the interval of DeadlockWatcher when none is given
+const defaultDeadlockInterval = 10 * time.Second
+
+// DeadlockWatcher polls SHOW ENGINE INNODB STATUS and calls OnDeadlock once for each new deadlock.
+// The deadlock detected before the first poll is reported as well.
+type DeadlockWatcher struct {
+	DB         *sql.DB
+	Interval   time.Duration // defaultDeadlockInterval if not positive
+	OnDeadlock func(*Deadlock)
+	OnError    func(error) // optional, errors are ignored if it is nil
+	last       string
+}
+
+// Poll fetch the status once and call OnDeadlock if the latest deadlock has not been reported
+func (w *DeadlockWatcher) Poll() error {
+	s, err := GetInnoDBStatus(w.DB)
+	if err != nil {
+		return err
+	}
+	w.observe(s.LatestDeadlock)
+	return nil
+}
+
+// observe report d if it differs from the last reported one
+func (w *DeadlockWatcher) observe(d *Deadlock) {
+	if d == nil || d.Text == w.last {
+		return
+	}
+	w.last = d.Text
+	if w.OnDeadlock != nil {
+		w.OnDeadlock(d)
+	}
+}
+
+// Run poll every Interval until stop is closed
+func (w *DeadlockWatcher) Run(stop <-chan struct{}) {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = defaultDeadlockInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := w.Poll(); err != nil && w.OnError != nil {
+			w.OnError(err)
+		}
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
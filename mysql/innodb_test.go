@@ -0,0 +1,110 @@
+package mysql
+
+import (
+	"strings"
+	"testing"
+)
+
+const testInnoDBStatus = `
+=====================================
+2018-10-11 12:00:05 0x7f2c40e1e700 INNODB MONITOR OUTPUT
+=====================================
+Per second averages calculated from the last 5 seconds
+-----------------
+BACKGROUND THREAD
+-----------------
+srv_master_thread loops: 10 srv_active, 0 srv_shutdown, 100 srv_idle
+----------
+SEMAPHORES
+----------
+OS WAIT ARRAY INFO: reservation count 12
+OS WAIT ARRAY INFO: signal count 10
+RW-shared spins 0, rounds 4, OS waits 2
+RW-excl spins 0, rounds 30, OS waits 1
+RW-sx spins 0, rounds 0, OS waits 0
+Spin rounds per wait: 4.00 RW-shared, 30.00 RW-excl, 0.00 RW-sx
+------------------------
+LATEST DETECTED DEADLOCK
+------------------------
+2018-10-11 11:59:58 0x7f2c40e1e700
+*** (1) TRANSACTION:
+TRANSACTION 2345, ACTIVE 10 sec starting index read
+mysql tables in use 1, locked 1
+LOCK WAIT 3 lock struct(s), heap size 1136, 2 row lock(s)
+MySQL thread id 8, OS thread handle 139827, query id 100 localhost root updating
+UPDATE t SET a = 1 WHERE id = 2
+*** (1) WAITING FOR THIS LOCK TO BE GRANTED:
+RECORD LOCKS space id 2 page no 4 n bits 72 index PRIMARY of table ` + "`test`.`t`" + ` trx id 2345 lock_mode X locks rec but not gap waiting
+Record lock, heap no 3 PHYSICAL RECORD: n_fields 4; compact format; info bits 0
+*** (2) TRANSACTION:
+TRANSACTION 2346, ACTIVE 8 sec starting index read
+MySQL thread id 9, OS thread handle 139828, query id 101 localhost root updating
+UPDATE t SET a = 2
+ WHERE id = 1
+*** (2) HOLDS THE LOCK(S):
+RECORD LOCKS space id 2 page no 4 n bits 72 index PRIMARY of table ` + "`test`.`t`" + ` trx id 2346 lock_mode X locks rec but not gap
+*** (2) WAITING FOR THIS LOCK TO BE GRANTED:
+RECORD LOCKS space id 2 page no 4 n bits 72 index PRIMARY of table ` + "`test`.`t`" + ` trx id 2346 lock_mode X locks rec but not gap waiting
+*** WE ROLL BACK TRANSACTION (1)
+----------------------
+BUFFER POOL AND MEMORY
+----------------------
+Total large memory allocated 137428992
+Dictionary memory allocated 116547
+Buffer pool size   8191
+Free buffers       7000
+Database pages     1100
+Old database pages 400
+Modified db pages  3
+Buffer pool hit rate 990 / 1000, young-making rate 0 / 1000 not 0 / 1000
+--------------
+ROW OPERATIONS
+--------------
+0 queries inside InnoDB, 0 queries in queue
+Number of rows inserted 10, updated 2, deleted 1, read 100
+0.20 inserts/s, 0.00 updates/s, 0.00 deletes/s, 15.80 reads/s
+----------------------------
+END OF INNODB MONITOR OUTPUT
+============================
+`
+
+func Test_ParseInnoDBStatus(t *testing.T) {
+	s := ParseInnoDBStatus(testInnoDBStatus)
+	if _, ok := s.Sections["BACKGROUND THREAD"]; !ok {
+		t.Error(s.Sections)
+	}
+	if body := s.Sections[InnoDBSectionRowOperations]; !strings.HasSuffix(body, "15.80 reads/s") {
+		t.Errorf("%q", body)
+	}
+
+	d := s.LatestDeadlock
+	if d == nil || d.RolledBack != 1 || len(d.Transactions) != 2 || d.Time.Format("15:04:05") != "11:59:58" {
+		t.Fatal(d)
+	}
+	t1, t2 := d.Transactions[0], d.Transactions[1]
+	if t1.TrxID != "2345" || t1.Thread != 8 || t1.Query != "UPDATE t SET a = 1 WHERE id = 2" || len(t1.WaitsLocks) != 1 || len(t1.HoldsLocks) != 0 {
+		t.Error(t1)
+	}
+	if t2.TrxID != "2346" || t2.Thread != 9 || t2.Query != "UPDATE t SET a = 2\nWHERE id = 1" || len(t2.HoldsLocks) != 1 || len(t2.WaitsLocks) != 1 {
+		t.Error(t2)
+	}
+
+	if b := s.BufferPool; b.TotalMemory != 137428992 || b.Size != 8191 || b.Free != 7000 || b.DatabasePages != 1100 || b.ModifiedPages != 3 || b.HitRate != 0.99 {
+		t.Error(b)
+	}
+	if r := s.RowOperations; r.Inserted != 10 || r.Updated != 2 || r.Deleted != 1 || r.Read != 100 || r.InsertsPerSec != 0.2 || r.ReadsPerSec != 15.8 {
+		t.Error(r)
+	}
+	if sem := s.Semaphores; sem.ReservationCount != 12 || sem.SignalCount != 10 || sem.OSWaits != 3 {
+		t.Error(sem)
+	}
+
+	var reported int
+	w := &DeadlockWatcher{OnDeadlock: func(*Deadlock) { reported++ }}
+	w.observe(d)
+	w.observe(ParseInnoDBStatus(testInnoDBStatus).LatestDeadlock)
+	w.observe(nil)
+	if reported != 1 {
+		t.Error(reported)
+	}
+}
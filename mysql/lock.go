@@ -0,0 +1,149 @@
+package mysql
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// lockWaitSQLs select the lock waits joined with INNODB_TRX, the first one for 5.7 and the second one for 8.0,
+// which moved the lock views to performance_schema.
+var lockWaitSQLs = []string{
+	`SELECT r.trx_id, r.trx_mysql_thread_id, COALESCE(r.trx_query, ''), 
+		b.trx_id, b.trx_mysql_thread_id, COALESCE(b.trx_query, ''), 
+		l.lock_table, COALESCE(l.lock_index, ''), l.lock_mode, 
+		TIMESTAMPDIFF(SECOND, r.trx_wait_started, NOW()) 
+		FROM information_schema.INNODB_LOCK_WAITS w 
+		JOIN information_schema.INNODB_TRX b ON b.trx_id = w.blocking_trx_id 
+		JOIN information_schema.INNODB_TRX r ON r.trx_id = w.requesting_trx_id 
+		JOIN information_schema.INNODB_LOCKS l ON l.lock_id = w.requested_lock_id 
+		ORDER BY r.trx_wait_started`,
+
+	`SELECT r.trx_id, r.trx_mysql_thread_id, COALESCE(r.trx_query, ''), 
+		b.trx_id, b.trx_mysql_thread_id, COALESCE(b.trx_query, ''), 
+		CONCAT(l.OBJECT_SCHEMA, '.', l.OBJECT_NAME), COALESCE(l.INDEX_NAME, ''), l.LOCK_MODE, 
+		TIMESTAMPDIFF(SECOND, r.trx_wait_started, NOW()) 
+		FROM performance_schema.data_lock_waits w 
+		JOIN information_schema.INNODB_TRX b ON b.trx_id = w.BLOCKING_ENGINE_TRANSACTION_ID 
+		JOIN information_schema.INNODB_TRX r ON r.trx_id = w.REQUESTING_ENGINE_TRANSACTION_ID 
+		JOIN performance_schema.data_locks l ON l.ENGINE_LOCK_ID = w.REQUESTED_ENGINE_LOCK_ID 
+		ORDER BY r.trx_wait_started`,
+}
+
+// LockWait is a transaction waiting for a lock held by another transaction
+type LockWait struct {
+	WaitingTrxID   string
+	WaitingThread  uint64 // ID of the waiting connection in PROCESSLIST
+	WaitingQuery   string
+	BlockingTrxID  string
+	BlockingThread uint64 // ID of the blocking connection in PROCESSLIST
+	BlockingQuery  string // empty when the blocking transaction is idle
+	LockedTable    string // database.table
+	LockedIndex    string
+	LockMode       string
+	Wait           time.Duration
+}
+
+// LockWaitNode is a transaction in the blocking tree, blocking the transactions in Blocked
+type LockWaitNode struct {
+	TrxID   string
+	Thread  uint64
+	Query   string
+	Wait    *LockWait // the wait on the parent node, nil for a root
+	Blocked []*LockWaitNode
+}
+
+// ListLockWaits get the lock waits, the longest waiting first.
+// information_schema.INNODB_LOCK_WAITS is used before 8.0, performance_schema.data_lock_waits otherwise.
+func ListLockWaits(db *sql.DB) ([]LockWait, error) {
+	v, err := getServerVersion(db)
+	if err != nil {
+		return nil, err
+	}
+	query := lockWaitSQLs[0]
+	if v.atLeast(8, 0, 0) {
+		query = lockWaitSQLs[1]
+	}
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var waits []LockWait
+	for rows.Next() {
+		var (
+			w       LockWait
+			seconds sql.NullInt64
+		)
+		err = rows.Scan(&w.WaitingTrxID, &w.WaitingThread, &w.WaitingQuery,
+			&w.BlockingTrxID, &w.BlockingThread, &w.BlockingQuery,
+			&w.LockedTable, &w.LockedIndex, &w.LockMode, &seconds,
+		)
+		if err != nil {
+			return nil, err
+		}
+		// 5.7 reports `database`.`table`
+		w.LockedTable = strings.Replace(w.LockedTable, "`", "", -1)
+		w.Wait = time.Duration(seconds.Int64) * time.Second
+		waits = append(waits, w)
+	}
+	return waits, rows.Err()
+}
+
+// LockWaits get the blocking tree, each root is a transaction blocking others without waiting itself.
+func LockWaits(db *sql.DB) ([]*LockWaitNode, error) {
+	waits, err := ListLockWaits(db)
+	if err != nil {
+		return nil, err
+	}
+	return blockingTree(waits), nil
+}
+
+// blockingTree build the blocking tree from waits. A transaction blocked by several others appears under each of them,
+// and transactions waiting on each other in a cycle only appear under a root blocking one of them.
+func blockingTree(waits []LockWait) []*LockWaitNode {
+	type trx struct {
+		id    string
+		query string
+	}
+	var (
+		blockers []uint64
+		waiting  = make(map[uint64]bool)
+		blocked  = make(map[uint64][]int)
+		info     = make(map[uint64]trx)
+	)
+	for i, w := range waits {
+		if _, ok := blocked[w.BlockingThread]; !ok {
+			blockers = append(blockers, w.BlockingThread)
+		}
+		blocked[w.BlockingThread] = append(blocked[w.BlockingThread], i)
+		waiting[w.WaitingThread] = true
+		info[w.WaitingThread] = trx{w.WaitingTrxID, w.WaitingQuery}
+		if _, ok := info[w.BlockingThread]; !ok {
+			info[w.BlockingThread] = trx{w.BlockingTrxID, w.BlockingQuery}
+		}
+	}
+
+	path := make(map[uint64]bool)
+	var build func(thread uint64, wait *LockWait) *LockWaitNode
+	build = func(thread uint64, wait *LockWait) *LockWaitNode {
+		node := &LockWaitNode{TrxID: info[thread].id, Thread: thread, Query: info[thread].query, Wait: wait}
+		path[thread] = true
+		for _, i := range blocked[thread] {
+			if w := waits[i]; !path[w.WaitingThread] {
+				node.Blocked = append(node.Blocked, build(w.WaitingThread, &w))
+			}
+		}
+		delete(path, thread)
+		return node
+	}
+
+	var roots []*LockWaitNode
+	for _, thread := range blockers {
+		if !waiting[thread] {
+			roots = append(roots, build(thread, nil))
+		}
+	}
+	return roots
+}
@@ -0,0 +1,28 @@
+package mysql
+
+import (
+	"testing"
+)
+
+func Test_blockingTree(t *testing.T) {
+	// 1 blocks 2 and 3, 2 blocks 4, 5 and 6 wait on each other and 1 blocks 5
+	roots := blockingTree([]LockWait{
+		{WaitingThread: 2, WaitingTrxID: "t2", BlockingThread: 1, BlockingTrxID: "t1"},
+		{WaitingThread: 3, WaitingTrxID: "t3", BlockingThread: 1, BlockingTrxID: "t1"},
+		{WaitingThread: 4, WaitingTrxID: "t4", BlockingThread: 2, BlockingTrxID: "t2"},
+		{WaitingThread: 5, WaitingTrxID: "t5", BlockingThread: 6, BlockingTrxID: "t6"},
+		{WaitingThread: 6, WaitingTrxID: "t6", BlockingThread: 5, BlockingTrxID: "t5"},
+		{WaitingThread: 5, WaitingTrxID: "t5", BlockingThread: 1, BlockingTrxID: "t1"},
+	})
+	if len(roots) != 1 || roots[0].Thread != 1 || roots[0].Wait != nil || len(roots[0].Blocked) != 3 {
+		t.Fatal(roots)
+	}
+	n2 := roots[0].Blocked[0]
+	if n2.TrxID != "t2" || n2.Wait.BlockingThread != 1 || len(n2.Blocked) != 1 || n2.Blocked[0].Thread != 4 {
+		t.Error(n2)
+	}
+	n5 := roots[0].Blocked[2]
+	if n5.Thread != 5 || len(n5.Blocked) != 1 || n5.Blocked[0].Thread != 6 || len(n5.Blocked[0].Blocked) != 0 {
+		t.Error(n5)
+	}
+}
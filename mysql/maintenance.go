@@ -0,0 +1,126 @@
+package mysql
+
+import (
+	"database/sql"
+	"strings"
+)
+
+// Msg_type values returned by table maintenance statements
+const (
+	MaintenanceMsgStatus  = "status"
+	MaintenanceMsgError   = "error"
+	MaintenanceMsgInfo    = "info"
+	MaintenanceMsgNote    = "note"
+	MaintenanceMsgWarning = "warning"
+)
+
+// MaintenanceMessage is a row returned by ANALYZE, OPTIMIZE, CHECK or REPAIR TABLE.
+type MaintenanceMessage struct {
+	Table   string // database.table
+	Op      string // analyze | optimize | check | repair
+	MsgType string // status | error | info | note | warning
+	MsgText string
+}
+
+// MaintenanceResult is the result of a maintenance statement on one table.
+type MaintenanceResult struct {
+	Table    string // database.table
+	Messages []MaintenanceMessage
+	Status   string // Msg_text of the status row, e.g. OK or Table is already up to date
+	OK       bool   // there is no error row and the status is successful
+}
+
+// AnalyzeTables run ANALYZE TABLE on the tables in schemas.
+func AnalyzeTables(db *sql.DB, schemas ...string) ([]MaintenanceResult, error) {
+	return maintainTables(db, "ANALYZE TABLE ", schemas)
+}
+
+// OptimizeTables run OPTIMIZE TABLE on the tables in schemas.
+func OptimizeTables(db *sql.DB, schemas ...string) ([]MaintenanceResult, error) {
+	return maintainTables(db, "OPTIMIZE TABLE ", schemas)
+}
+
+// CheckTables run CHECK TABLE on the tables in schemas.
+func CheckTables(db *sql.DB, schemas ...string) ([]MaintenanceResult, error) {
+	return maintainTables(db, "CHECK TABLE ", schemas)
+}
+
+// RepairTables run REPAIR TABLE on the tables in schemas, InnoDB tables report that repair is not supported.
+func RepairTables(db *sql.DB, schemas ...string) ([]MaintenanceResult, error) {
+	return maintainTables(db, "REPAIR TABLE ", schemas)
+}
+
+// maintainTables run the statement on all tables in schemas and collect the results.
+// Use the currently selected database if a schema does not contain database.
+func maintainTables(db *sql.DB, statement string, schemas []string) ([]MaintenanceResult, error) {
+	if len(schemas) == 0 {
+		return nil, errEmptyParamTable
+	}
+	tables := make([]string, len(schemas))
+	for i, schema := range schemas {
+		database, table, err := parseTableSchema(db, schema)
+		if err != nil {
+			return nil, err
+		}
+		tables[i] = database + "." + table
+	}
+
+	rows, err := db.Query(statement + strings.Join(tables, ","))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []MaintenanceMessage
+	for rows.Next() {
+		var m MaintenanceMessage
+		if err = rows.Scan(&m.Table, &m.Op, &m.MsgType, &m.MsgText); err != nil {
+			return nil, err
+		}
+		messages = append(messages, m)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return groupMaintenanceMessages(messages), nil
+}
+
+// groupMaintenanceMessages group messages by table in the order they were returned
+func groupMaintenanceMessages(messages []MaintenanceMessage) []MaintenanceResult {
+	var results []MaintenanceResult
+	index := make(map[string]int)
+	for _, m := range messages {
+		i, ok := index[m.Table]
+		if !ok {
+			i = len(results)
+			index[m.Table] = i
+			results = append(results, MaintenanceResult{Table: m.Table, OK: true})
+		}
+		r := &results[i]
+		r.Messages = append(r.Messages, m)
+		switch strings.ToLower(m.MsgType) {
+		case MaintenanceMsgError:
+			r.OK = false
+		case MaintenanceMsgStatus:
+			r.Status = m.MsgText
+			if !maintenanceStatusOK(m.MsgText) {
+				r.OK = false
+			}
+		}
+	}
+	for i := range results {
+		if results[i].Status == "" {
+			results[i].OK = false
+		}
+	}
+	return results
+}
+
+// maintenanceStatusOK report whether the Msg_text of a status row means success
+func maintenanceStatusOK(text string) bool {
+	switch text {
+	case "OK", "Table is already up to date":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+package mysql
+
+import (
+	"testing"
+)
+
+func Test_groupMaintenanceMessages(t *testing.T) {
+	results := groupMaintenanceMessages([]MaintenanceMessage{
+		{"db.a", "optimize", "note", "Table does not support optimize, doing recreate + analyze instead"},
+		{"db.a", "optimize", "status", "OK"},
+		{"db.b", "check", "error", "Table 'db.b' doesn't exist"},
+		{"db.b", "check", "status", "Operation failed"},
+		{"db.c", "analyze", "status", "Table is already up to date"},
+	})
+	if len(results) != 3 {
+		t.Fatal(results)
+	}
+	if !results[0].OK || len(results[0].Messages) != 2 || results[0].Status != "OK" {
+		t.Error(results[0])
+	}
+	if results[1].OK || results[1].Status != "Operation failed" {
+		t.Error(results[1])
+	}
+	if !results[2].OK {
+		t.Error(results[2])
+	}
+}
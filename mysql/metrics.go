@@ -0,0 +1,263 @@
+package mysql
+
+import (
+	"bytes"
+	"database/sql"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultStatusCounters are the global status variables exported by Collector when StatusCounters is nil
+var DefaultStatusCounters = []string{
+	"Questions",
+	"Slow_queries",
+	"Threads_connected",
+	"Threads_running",
+	"Aborted_connects",
+	"Innodb_rows_read",
+	"Innodb_rows_inserted",
+	"Innodb_rows_updated",
+	"Innodb_rows_deleted",
+	"Innodb_row_lock_waits",
+}
+
+// defaultCollectInterval is the interval of Collector when none is given
+const defaultCollectInterval = 15 * time.Second
+
+// Collector periodically gathers the processes, transactions, events, lock waits, table sizes and
+// selected global status of a server, and serves them in the Prometheus text exposition format.
+type Collector struct {
+	DB             *sql.DB
+	Interval       time.Duration // defaultCollectInterval if not positive
+	Databases      []string      // export the sizes of the tables in these databases
+	StatusCounters []string      // DefaultStatusCounters if nil
+	OnError        func(error)
+
+	mu   sync.RWMutex
+	text []byte
+}
+
+// metricFamily is a metric with its samples
+type metricFamily struct {
+	name    string
+	help    string
+	typ     string // gauge | counter | untyped
+	samples []metricSample
+}
+
+// metricSample is a value with labels, which are pairs of name and value
+type metricSample struct {
+	labels []string
+	value  float64
+}
+
+// add append a sample with labels given as name, value, name, value...
+func (f *metricFamily) add(value float64, labels ...string) {
+	f.samples = append(f.samples, metricSample{labels: labels, value: value})
+}
+
+// Run collect every Interval until stop is closed
+func (c *Collector) Run(stop <-chan struct{}) {
+	interval := c.Interval
+	if interval <= 0 {
+		interval = defaultCollectInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		c.Collect()
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Collect gather the metrics once, failed parts are reported to OnError and left out.
+func (c *Collector) Collect() {
+	var families []*metricFamily
+	up := &metricFamily{name: "mysql_up", help: "Whether the server answered the last collection.", typ: "gauge"}
+	families = append(families, up)
+
+	processes, err := NumProcess(c.DB)
+	if err != nil {
+		c.report(err)
+		up.add(0)
+		c.store(families)
+		return
+	}
+	up.add(1)
+	families = append(families, &metricFamily{
+		name: "mysql_processes", help: "Number of rows in PROCESSLIST.", typ: "gauge",
+		samples: []metricSample{{value: float64(processes)}},
+	})
+
+	if n, err := NumTransaction(c.DB); err != nil {
+		c.report(err)
+	} else {
+		families = append(families, &metricFamily{
+			name: "mysql_innodb_transactions", help: "Number of running InnoDB transactions.", typ: "gauge",
+			samples: []metricSample{{value: float64(n)}},
+		})
+	}
+
+	if f, err := c.collectEvents(); err != nil {
+		c.report(err)
+	} else {
+		families = append(families, f)
+	}
+
+	if waits, err := ListLockWaits(c.DB); err != nil {
+		c.report(err)
+	} else {
+		families = append(families, &metricFamily{
+			name: "mysql_innodb_lock_waits", help: "Number of transactions waiting for a lock.", typ: "gauge",
+			samples: []metricSample{{value: float64(len(waits))}},
+		})
+	}
+
+	if len(c.Databases) > 0 {
+		if fs, err := c.collectTables(); err != nil {
+			c.report(err)
+		} else {
+			families = append(families, fs...)
+		}
+	}
+
+	if fs, err := c.collectStatus(); err != nil {
+		c.report(err)
+	} else {
+		families = append(families, fs...)
+	}
+	c.store(families)
+}
+
+// collectEvents count the events by status
+func (c *Collector) collectEvents() (*metricFamily, error) {
+	f := &metricFamily{name: "mysql_events", help: "Number of events by status.", typ: "gauge"}
+	for _, status := range []string{EventStatusEnabled, EventStatusDisabled, EventStatusSlavesideDisable} {
+		n, err := NumAllEventWithStatus(c.DB, status)
+		if err != nil {
+			return nil, err
+		}
+		f.add(float64(n), "status", status)
+	}
+	return f, nil
+}
+
+// collectTables get the sizes of the tables in Databases
+func (c *Collector) collectTables() ([]*metricFamily, error) {
+	stats, err := ListTableStats(c.DB, c.Databases...)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		tableRows  = &metricFamily{name: "mysql_table_rows", help: "Estimated number of rows of a table.", typ: "gauge"}
+		dataBytes  = &metricFamily{name: "mysql_table_data_bytes", help: "DATA_LENGTH of a table.", typ: "gauge"}
+		indexBytes = &metricFamily{name: "mysql_table_index_bytes", help: "INDEX_LENGTH of a table.", typ: "gauge"}
+		freeBytes  = &metricFamily{name: "mysql_table_free_bytes", help: "DATA_FREE of a table.", typ: "gauge"}
+	)
+	for _, s := range stats {
+		tableRows.add(float64(s.Rows), "database", s.Schema, "table", s.Table)
+		dataBytes.add(float64(s.DataLength), "database", s.Schema, "table", s.Table)
+		indexBytes.add(float64(s.IndexLength), "database", s.Schema, "table", s.Table)
+		freeBytes.add(float64(s.DataFree), "database", s.Schema, "table", s.Table)
+	}
+	return []*metricFamily{tableRows, dataBytes, indexBytes, freeBytes}, nil
+}
+
+// collectStatus get the selected global status variables, each one is a metric named mysql_global_status_<name>
+func (c *Collector) collectStatus() ([]*metricFamily, error) {
+	snapshot, err := GetStatus(c.DB)
+	if err != nil {
+		return nil, err
+	}
+	names := c.StatusCounters
+	if names == nil {
+		names = DefaultStatusCounters
+	}
+	var families []*metricFamily
+	for _, name := range names {
+		value, err := snapshot.Get(name).Float()
+		if err != nil {
+			continue
+		}
+		families = append(families, &metricFamily{
+			name:    "mysql_global_status_" + strings.ToLower(name),
+			help:    "Global status " + name + ".",
+			typ:     "untyped",
+			samples: []metricSample{{value: value}},
+		})
+	}
+	return families, nil
+}
+
+// report pass err to OnError
+func (c *Collector) report(err error) {
+	if c.OnError != nil {
+		c.OnError(err)
+	}
+}
+
+// store render families and keep the text for ServeHTTP
+func (c *Collector) store(families []*metricFamily) {
+	text := renderMetrics(families)
+	c.mu.Lock()
+	c.text = text
+	c.mu.Unlock()
+}
+
+// ServeHTTP write the last collected metrics
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.RLock()
+	text := c.text
+	c.mu.RUnlock()
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write(text)
+}
+
+// renderMetrics render families in the Prometheus text exposition format, sorted by name
+func renderMetrics(families []*metricFamily) []byte {
+	sort.SliceStable(families, func(i, j int) bool { return families[i].name < families[j].name })
+	var buf bytes.Buffer
+	for _, f := range families {
+		buf.WriteString("# HELP " + f.name + " " + f.help + "\n")
+		buf.WriteString("# TYPE " + f.name + " " + f.typ + "\n")
+		for _, s := range f.samples {
+			buf.WriteString(f.name)
+			if len(s.labels) > 0 {
+				buf.WriteByte('{')
+				for i := 0; i+1 < len(s.labels); i += 2 {
+					if i > 0 {
+						buf.WriteByte(',')
+					}
+					buf.WriteString(s.labels[i] + `="` + escapeLabelValue(s.labels[i+1]) + `"`)
+				}
+				buf.WriteByte('}')
+			}
+			buf.WriteString(" " + strconv.FormatFloat(s.value, 'g', -1, 64) + "\n")
+		}
+	}
+	return buf.Bytes()
+}
+
+// escapeLabelValue escape backslash, double quote and line feed in a label value
+func escapeLabelValue(v string) string {
+	v = strings.Replace(v, `\`, `\\`, -1)
+	v = strings.Replace(v, `"`, `\"`, -1)
+	return strings.Replace(v, "\n", `\n`, -1)
+}
+
+// stringArgs convert strings to query arguments
+func stringArgs(values []string) []interface{} {
+	args := make([]interface{}, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	return args
+}
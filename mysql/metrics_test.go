@@ -0,0 +1,30 @@
+package mysql
+
+import (
+	"testing"
+)
+
+func Test_renderMetrics(t *testing.T) {
+	events := &metricFamily{name: "mysql_events", help: "Number of events by status.", typ: "gauge"}
+	events.add(3, "status", "ENABLED")
+	events.add(1, "status", `DIS"ABLED`)
+	tables := &metricFamily{name: "mysql_table_rows", help: "Estimated number of rows of a table.", typ: "gauge"}
+	tables.add(1.5e+07, "database", "mydb", "table", "user")
+	up := &metricFamily{name: "mysql_up", help: "Whether the server answered the last collection.", typ: "gauge"}
+	up.add(1)
+
+	expect := `# HELP mysql_events Number of events by status.
+# TYPE mysql_events gauge
+mysql_events{status="ENABLED"} 3
+mysql_events{status="DIS\"ABLED"} 1
+# HELP mysql_table_rows Estimated number of rows of a table.
+# TYPE mysql_table_rows gauge
+mysql_table_rows{database="mydb",table="user"} 1.5e+07
+# HELP mysql_up Whether the server answered the last collection.
+# TYPE mysql_up gauge
+mysql_up 1
+`
+	if s := string(renderMetrics([]*metricFamily{up, tables, events})); s != expect {
+		t.Errorf("expect:\n%s\ngot:\n%s", expect, s)
+	}
+}
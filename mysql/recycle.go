@@ -0,0 +1,178 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// recycleBinTable is the table in the trash database recording the dropped tables
+const recycleBinTable = "recycle_bin"
+
+// recycleBinEntry is the structure of recycleBinTable
+type recycleBinEntry struct {
+	ID             int64      `mysql:"id, primarykey, autoincrement, notnull"`
+	TrashedTable   string     `mysql:"trashed_table, unique, notnull, size:64"`
+	OriginalSchema string     `mysql:"original_schema, notnull, size:64"`
+	OriginalTable  string     `mysql:"original_table, notnull, size:64"`
+	DroppedAt      *time.Time `mysql:"dropped_at, notnull"`
+}
+
+// DroppedTable is a table kept in the recycle bin
+type DroppedTable struct {
+	Name           string // name of the table in the trash database
+	OriginalSchema string
+	OriginalTable  string
+	DroppedAt      time.Time
+}
+
+// moveToRecycleBin rename database.table into the trash database p.RecycleBin and record it,
+// which is what the drop functions do instead of DROP TABLE when p.RecycleBin is set.
+// The record is inserted first and deleted if the rename fails, so that a trashed table is never left unrecorded.
+func (p *DropPolicy) moveToRecycleBin(db *sql.DB, database, table string) error {
+	trash := strings.Trim(p.RecycleBin, " ")
+	if trash == "" {
+		return errEmptyRecycleBin
+	}
+	if isSystemDatabase(trash) {
+		return errDropSystemDatabase
+	}
+	if database == trash {
+		return errDropFromRecycleBin
+	}
+	trashed := trashedTableName(database, table, time.Now())
+	record := "INSERT INTO " + trash + "." + recycleBinTable + "(trashed_table, original_schema, original_table, dropped_at) " +
+		"VALUES(" + quoteString(trashed) + ", " + quoteString(database) + ", " + quoteString(table) + ", NOW())"
+	rename := "RENAME TABLE " + database + "." + table + " TO " + trash + "." + trashed
+	if p.DryRun != nil {
+		p.DryRun(record)
+		p.DryRun(rename)
+		return nil
+	}
+	if err := prepareRecycleBin(db, trash); err != nil {
+		return err
+	}
+	if _, err := db.Exec(record); err != nil {
+		return err
+	}
+	if _, err := db.Exec(rename); err != nil {
+		db.Exec("DELETE FROM "+trash+"."+recycleBinTable+" WHERE trashed_table = ?", trashed)
+		return err
+	}
+	return nil
+}
+
+// prepareRecycleBin create the trash database and its recycleBinTable if not exist
+func prepareRecycleBin(db *sql.DB, trash string) error {
+	if err := CreateDatabaseIfNotExist(db, trash); err != nil {
+		return err
+	}
+	return CreateTableWithSchemaIfNotExist(db, recycleBinEntry{}, trash+"."+recycleBinTable)
+}
+
+// trashedTableName build the name of a dropped table in the trash database, such as mydb__user_20181011120000123456_k3f9.
+// The suffix holds the time to the microsecond and a random part, so that tables dropped in the same second,
+// or whose names are truncated to the same prefix, do not collide. The base name is truncated to keep the suffix.
+func trashedTableName(database, table string, at time.Time) string {
+	name := database + "__" + table
+	random := strconv.FormatInt(rand.Int63n(36*36*36*36), 36)
+	suffix := "_" + at.Format("20060102150405") + fmt.Sprintf("%06d", at.Nanosecond()/1000) + "_" + strings.Repeat("0", 4-len(random)) + random
+	if len(name)+len(suffix) > 64 {
+		name = name[:64-len(suffix)]
+	}
+	return name + suffix
+}
+
+// ListDropped list the tables in the recycle bin of trash database, the latest dropped first
+func ListDropped(db *sql.DB, trash string) ([]DroppedTable, error) {
+	trash = strings.Trim(trash, " ")
+	if trash == "" {
+		return nil, errEmptyParamDatabase
+	}
+	return listDropped(db, trash, "")
+}
+
+// listDropped query the recycle bin with an optional condition
+func listDropped(db *sql.DB, trash, where string, args ...interface{}) ([]DroppedTable, error) {
+	isexist, err := TableExist(db, trash+"."+recycleBinTable)
+	if err != nil || !isexist {
+		return nil, err
+	}
+	query := "SELECT trashed_table, original_schema, original_table, UNIX_TIMESTAMP(dropped_at) " +
+		"FROM " + trash + "." + recycleBinTable
+	if where != "" {
+		query += " WHERE " + where
+	}
+	rows, err := db.Query(query+" ORDER BY dropped_at DESC, id DESC", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var dropped []DroppedTable
+	for rows.Next() {
+		var (
+			d  DroppedTable
+			at int64
+		)
+		if err = rows.Scan(&d.Name, &d.OriginalSchema, &d.OriginalTable, &at); err != nil {
+			return nil, err
+		}
+		d.DroppedAt = time.Unix(at, 0)
+		dropped = append(dropped, d)
+	}
+	return dropped, rows.Err()
+}
+
+// RestoreTable move the table named trashed in the recycle bin of trash database back.
+// The table is restored to where it was dropped if target is empty.
+func RestoreTable(db *sql.DB, trash, trashed, target string) error {
+	trash, trashed = strings.Trim(trash, " "), strings.Trim(trashed, " ")
+	if trash == "" {
+		return errEmptyParamDatabase
+	}
+	if trashed == "" {
+		return errEmptyParamTable
+	}
+	dropped, err := listDropped(db, trash, "trashed_table = ?", trashed)
+	if err != nil {
+		return err
+	}
+	if len(dropped) == 0 {
+		return errDroppedTableNotInRecycleBin
+	}
+	if strings.Trim(target, " ") == "" {
+		target = dropped[0].OriginalSchema + "." + dropped[0].OriginalTable
+	}
+	if err = RenameTable(db, trash+"."+trashed, target); err != nil {
+		return err
+	}
+	_, err = db.Exec("DELETE FROM "+trash+"."+recycleBinTable+" WHERE trashed_table = ?", trashed)
+	return err
+}
+
+// PurgeExpired permanently drop the tables which stay in the recycle bin of trash database longer than retention,
+// return the number of tables dropped.
+func PurgeExpired(db *sql.DB, trash string, retention time.Duration) (int, error) {
+	trash = strings.Trim(trash, " ")
+	if trash == "" {
+		return 0, errEmptyParamDatabase
+	}
+	dropped, err := listDropped(db, trash,
+		"dropped_at < NOW() - INTERVAL "+strconv.FormatInt(int64(retention/time.Second), 10)+" SECOND")
+	if err != nil {
+		return 0, err
+	}
+	for i, d := range dropped {
+		if _, err = db.Exec("DROP TABLE IF EXISTS " + trash + "." + d.Name); err != nil {
+			return i, err
+		}
+		if _, err = db.Exec("DELETE FROM "+trash+"."+recycleBinTable+" WHERE trashed_table = ?", d.Name); err != nil {
+			return i, err
+		}
+	}
+	return len(dropped), nil
+}
@@ -0,0 +1,40 @@
+package mysql
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_trashedTableName(t *testing.T) {
+	at := time.Date(2018, 10, 11, 12, 0, 0, 500000000, time.UTC)
+	name := trashedTableName("mydb", "user", at)
+	if !strings.HasPrefix(name, "mydb__user_20181011120000500000_") || len(name) != len("mydb__user_20181011120000500000_k3f9") {
+		t.Error(name)
+	}
+	if other := trashedTableName("mydb", "user", at); other == name {
+		t.Error("expect different names in the same microsecond", name)
+	}
+	long := trashedTableName("mydb", strings.Repeat("t", 64), at)
+	if len(long) != 64 || !strings.Contains(long, "_20181011120000500000_") {
+		t.Error(long)
+	}
+}
+
+func Test_moveToRecycleBin(t *testing.T) {
+	if err := (&DropPolicy{RecycleBin: "  "}).moveToRecycleBin(nil, "mydb", "user"); err != errEmptyRecycleBin {
+		t.Error(err)
+	}
+	if err := (&DropPolicy{RecycleBin: " trash "}).moveToRecycleBin(nil, "trash", "user"); err != errDropFromRecycleBin {
+		t.Error(err)
+	}
+
+	var planned []string
+	p := &DropPolicy{RecycleBin: " trash ", DryRun: func(query string) { planned = append(planned, query) }}
+	if err := p.moveToRecycleBin(nil, "mydb", "user"); err != nil || len(planned) != 2 {
+		t.Fatal(err, planned)
+	}
+	if !strings.HasPrefix(planned[0], "INSERT INTO trash.recycle_bin") || !strings.HasPrefix(planned[1], "RENAME TABLE mydb.user TO trash.mydb__user_") {
+		t.Error(planned)
+	}
+}
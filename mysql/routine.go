@@ -0,0 +1,523 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Routine types
+const (
+	RoutineProcedure = "PROCEDURE"
+	RoutineFunction  = "FUNCTION"
+)
+
+// Parameter modes, functions only have ParamIn parameters
+const (
+	ParamIn    = "IN"
+	ParamOut   = "OUT"
+	ParamInOut = "INOUT"
+)
+
+// routineColumns are the columns scanned into DescRoutine
+const routineColumns = `ROUTINE_SCHEMA, ROUTINE_NAME, ROUTINE_TYPE, COALESCE(DTD_IDENTIFIER, ''), 
+	COALESCE(ROUTINE_DEFINITION, ''), IS_DETERMINISTIC, SQL_DATA_ACCESS, SECURITY_TYPE, 
+	CREATED, LAST_ALTERED, SQL_MODE, ROUTINE_COMMENT, DEFINER`
+
+// RoutineParam is a parameter of a stored procedure or function
+type RoutineParam struct {
+	Mode string // ParamIn | ParamOut | ParamInOut, ParamIn if empty
+	Name string
+	Type string // the data type with length, charset and so on, e.g. varchar(20)
+}
+
+// RoutineDef describes a stored procedure or function for CreateOrReplaceRoutine
+type RoutineDef struct {
+	Name            string
+	Type            string // RoutineProcedure | RoutineFunction
+	Params          []RoutineParam
+	Returns         string // the return type of a function
+	Characteristics string // optional, e.g. DETERMINISTIC READS SQL DATA
+	Comment         string
+	Body            string // a statement, or BEGIN ... END
+}
+
+// DescRoutine is the detail information of a stored procedure or function
+type DescRoutine struct {
+	Schema        string
+	Name          string
+	Type          string // PROCEDURE | FUNCTION
+	Returns       string // the return type of a function, empty for procedure
+	Definition    string // empty if the user is not the definer and lacks privilege
+	Deterministic bool
+	DataAccess    string // CONTAINS SQL | NO SQL | READS SQL DATA | MODIFIES SQL DATA
+	Security      string // DEFINER | INVOKER
+	Created       *time.Time
+	LastAltered   *time.Time
+	SQLMode       string
+	Comment       string
+	Definer       string
+	Params        []RoutineParam // only filled by DescribeRoutine
+}
+
+// scanRoutine scan a row selected with routineColumns
+func scanRoutine(s scanner) (*DescRoutine, error) {
+	dr := &DescRoutine{}
+	var deterministic string
+	err := s.Scan(&dr.Schema, &dr.Name, &dr.Type, &dr.Returns,
+		&dr.Definition, &deterministic, &dr.DataAccess, &dr.Security,
+		nullTime{&dr.Created}, nullTime{&dr.LastAltered}, &dr.SQLMode, &dr.Comment, &dr.Definer,
+	)
+	if err != nil {
+		return nil, err
+	}
+	dr.Deterministic = deterministic == "YES"
+	return dr, nil
+}
+
+// parseRoutineType check and normalize routineType
+func parseRoutineType(routineType string) (string, error) {
+	routineType = strings.ToUpper(strings.Trim(routineType, " "))
+	if routineType != RoutineProcedure && routineType != RoutineFunction {
+		return "", errInvalidRoutineType
+	}
+	return routineType, nil
+}
+
+// RoutineExist check wheather a stored procedure or function exist. Using current database when param database is empty.
+func RoutineExist(db *sql.DB, database, routine, routineType string) (bool, error) {
+	schema, err := objectSchema(db, database, routine, errEmptyParamRoutine)
+	if err != nil {
+		return false, err
+	}
+	if routineType, err = parseRoutineType(routineType); err != nil {
+		return false, err
+	}
+	database, routine = splitSchema(schema)
+	r := db.QueryRow(
+		`SELECT ROUTINE_NAME 
+			FROM information_schema.ROUTINES 
+			WHERE ROUTINE_SCHEMA = ? AND ROUTINE_NAME = ? AND ROUTINE_TYPE = ?`, database, routine, routineType,
+	)
+	return exist(r)
+}
+
+// ListRoutines get the stored procedures and functions in database, only those of routineType if it is not empty.
+// Using current database when param database is empty.
+func ListRoutines(db *sql.DB, database, routineType string) ([]*DescRoutine, error) {
+	database = strings.Trim(database, " ")
+	var err error
+	if database == "" {
+		database, err = getDatabaseName(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+	query := `SELECT ` + routineColumns + ` 
+			FROM information_schema.ROUTINES 
+			WHERE ROUTINE_SCHEMA = ?`
+	args := []interface{}{database}
+	if strings.Trim(routineType, " ") != "" {
+		if routineType, err = parseRoutineType(routineType); err != nil {
+			return nil, err
+		}
+		query += " AND ROUTINE_TYPE = ?"
+		args = append(args, routineType)
+	}
+	rows, err := db.Query(query+" ORDER BY ROUTINE_TYPE, ROUTINE_NAME", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var routines []*DescRoutine
+	for rows.Next() {
+		var dr *DescRoutine
+		if dr, err = scanRoutine(rows); err != nil {
+			return nil, err
+		}
+		routines = append(routines, dr)
+	}
+	return routines, rows.Err()
+}
+
+// DescribeRoutine get the detail information of a stored procedure or function with its parameters.
+// Using current database when param database is empty.
+func DescribeRoutine(db *sql.DB, database, routine, routineType string) (*DescRoutine, error) {
+	schema, err := objectSchema(db, database, routine, errEmptyParamRoutine)
+	if err != nil {
+		return nil, err
+	}
+	if routineType, err = parseRoutineType(routineType); err != nil {
+		return nil, err
+	}
+	database, routine = splitSchema(schema)
+	var dr *DescRoutine
+	dr, err = scanRoutine(db.QueryRow(
+		`SELECT `+routineColumns+` 
+			FROM information_schema.ROUTINES 
+			WHERE ROUTINE_SCHEMA = ? AND ROUTINE_NAME = ? AND ROUTINE_TYPE = ?`, database, routine, routineType,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errRoutineNotExist
+		}
+		return nil, err
+	}
+
+	// ORDINAL_POSITION 0 is the return value of a function
+	rows, err := db.Query(
+		`SELECT COALESCE(PARAMETER_MODE, ''), COALESCE(PARAMETER_NAME, ''), DTD_IDENTIFIER 
+			FROM information_schema.PARAMETERS 
+			WHERE SPECIFIC_SCHEMA = ? AND SPECIFIC_NAME = ? AND ROUTINE_TYPE = ? AND ORDINAL_POSITION > 0 
+			ORDER BY ORDINAL_POSITION`, database, routine, routineType,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p RoutineParam
+		if err = rows.Scan(&p.Mode, &p.Name, &p.Type); err != nil {
+			return nil, err
+		}
+		dr.Params = append(dr.Params, p)
+	}
+	return dr, rows.Err()
+}
+
+// CreateOrReplaceRoutine create a stored procedure or function, drop it first if exists.
+// MySQL has no CREATE OR REPLACE for routines, so the routine is missing for a moment.
+// If the creation fails, the previous routine and its grants are restored, so replacing a routine
+// whose definition the user can not read is refused.
+// Using current database when param database is empty.
+func CreateOrReplaceRoutine(db *sql.DB, database string, def RoutineDef) error {
+	schema, err := objectSchema(db, database, def.Name, errEmptyParamRoutine)
+	if err != nil {
+		return err
+	}
+	var query string
+	if query, err = def.sql(schema); err != nil {
+		return err
+	}
+	routineType := strings.ToUpper(strings.Trim(def.Type, " "))
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var backup *routineBackup
+	if backup, err = backupRoutine(ctx, conn, schema, routineType); err != nil {
+		return err
+	}
+	if _, err = conn.ExecContext(ctx, "DROP "+routineType+" IF EXISTS "+schema); err != nil {
+		return err
+	}
+	if _, err = conn.ExecContext(ctx, query); err != nil {
+		if backup != nil {
+			if restoreErr := backup.restore(ctx, conn); restoreErr != nil {
+				return fmt.Errorf("%v, and restoring %s failed: %v", err, schema, restoreErr)
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// routineBackup is what is needed to create a dropped routine again
+type routineBackup struct {
+	sqlMode string
+	create  string
+	grants  []string
+}
+
+// backupRoutine capture the definition and grants of a routine, return nil if it does not exist.
+// The grants are only captured if the user can read mysql.procs_priv.
+func backupRoutine(ctx context.Context, conn *sql.Conn, schema, routineType string) (*routineBackup, error) {
+	database, routine := splitSchema(schema)
+	var n int
+	err := conn.QueryRowContext(ctx,
+		`SELECT COUNT(0) 
+			FROM information_schema.ROUTINES 
+			WHERE ROUTINE_SCHEMA = ? AND ROUTINE_NAME = ? AND ROUTINE_TYPE = ?`, database, routine, routineType,
+	).Scan(&n)
+	if err != nil || n == 0 {
+		return nil, err
+	}
+
+	rows, err := conn.QueryContext(ctx, "SHOW CREATE "+routineType+" "+schema)
+	if err != nil {
+		return nil, err
+	}
+	sets, err := readResultSets(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+	if len(sets) == 0 || len(sets[0].Rows) == 0 || len(sets[0].Rows[0]) < 3 || sets[0].Rows[0][2] == nil {
+		// the definition is NULL without enough privilege, the routine could not be restored
+		return nil, errRoutineNotRestorable
+	}
+	b := &routineBackup{sqlMode: fmt.Sprint(sets[0].Rows[0][1]), create: fmt.Sprint(sets[0].Rows[0][2])}
+
+	rows, err = conn.QueryContext(ctx,
+		`SELECT User, Host, Proc_priv 
+			FROM mysql.procs_priv 
+			WHERE Db = ? AND Routine_name = ? AND Routine_type = ?`, database, routine, routineType,
+	)
+	if err != nil {
+		return b, nil
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var user, host, privileges string
+		if err = rows.Scan(&user, &host, &privileges); err != nil {
+			return nil, err
+		}
+		if grant := routineGrantSQL(routineType, schema, user, host, privileges); grant != "" {
+			b.grants = append(b.grants, grant)
+		}
+	}
+	return b, rows.Err()
+}
+
+// restore create the routine again with its sql_mode, and grant its privileges
+func (b *routineBackup) restore(ctx context.Context, conn *sql.Conn) error {
+	var sqlMode string
+	if err := conn.QueryRowContext(ctx, "SELECT @@SESSION.sql_mode").Scan(&sqlMode); err != nil {
+		return err
+	}
+	if _, err := conn.ExecContext(ctx, "SET SESSION sql_mode = ?", b.sqlMode); err != nil {
+		return err
+	}
+	_, err := conn.ExecContext(ctx, b.create)
+	if _, resetErr := conn.ExecContext(ctx, "SET SESSION sql_mode = ?", sqlMode); err == nil {
+		err = resetErr
+	}
+	if err != nil {
+		return err
+	}
+	for _, grant := range b.grants {
+		if _, err = conn.ExecContext(ctx, grant); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// routineGrantSQL render the GRANT of privileges, a value of mysql.procs_priv.Proc_priv such as Execute,Alter Routine,Grant
+func routineGrantSQL(routineType, schema, user, host, privileges string) string {
+	var (
+		privs       []string
+		grantOption bool
+	)
+	for _, p := range strings.Split(privileges, ",") {
+		switch p = strings.ToUpper(strings.Trim(p, " ")); p {
+		case "":
+		case "GRANT":
+			grantOption = true
+		default:
+			privs = append(privs, p)
+		}
+	}
+	if len(privs) == 0 {
+		return ""
+	}
+	grant := "GRANT " + strings.Join(privs, ", ") + " ON " + routineType + " " + schema + " TO " + quoteString(user) + "@" + quoteString(host)
+	if grantOption {
+		grant += " WITH GRANT OPTION"
+	}
+	return grant
+}
+
+// sql renders CREATE PROCEDURE or CREATE FUNCTION
+func (def *RoutineDef) sql(schema string) (string, error) {
+	routineType, err := parseRoutineType(def.Type)
+	if err != nil {
+		return "", err
+	}
+	body := strings.Trim(def.Body, " ")
+	if body == "" {
+		return "", errEmptyParamBody
+	}
+	params := make([]string, len(def.Params))
+	for i, p := range def.Params {
+		mode := strings.ToUpper(strings.Trim(p.Mode, " "))
+		switch {
+		case routineType == RoutineFunction && (mode == "" || mode == ParamIn):
+			params[i] = p.Name + " " + p.Type
+		case routineType == RoutineProcedure && (mode == "" || mode == ParamIn || mode == ParamOut || mode == ParamInOut):
+			if mode == "" {
+				mode = ParamIn
+			}
+			params[i] = mode + " " + p.Name + " " + p.Type
+		default:
+			return "", errInvalidParamMode
+		}
+	}
+	query := "CREATE " + routineType + " " + schema + "(" + strings.Join(params, ", ") + ")"
+	if routineType == RoutineFunction {
+		returns := strings.Trim(def.Returns, " ")
+		if returns == "" {
+			return "", errEmptyParamReturns
+		}
+		query += " RETURNS " + returns
+	}
+	if def.Comment != "" {
+		query += " COMMENT " + quoteString(def.Comment)
+	}
+	if characteristics := strings.Trim(def.Characteristics, " "); characteristics != "" {
+		query += " " + characteristics
+	}
+	return query + " " + body, nil
+}
+
+// DropRoutineIfExist drop a stored procedure or function if exists. Using current database when param database is empty.
+func DropRoutineIfExist(db *sql.DB, database, routine, routineType string) error {
+	schema, err := objectSchema(db, database, routine, errEmptyParamRoutine)
+	if err != nil {
+		return err
+	}
+	if routineType, err = parseRoutineType(routineType); err != nil {
+		return err
+	}
+	_, err = db.Exec("DROP " + routineType + " IF EXISTS " + schema)
+	return err
+}
+
+// CallParam is an argument of CallProcedure
+type CallParam struct {
+	Mode  string // ParamIn | ParamOut | ParamInOut, ParamIn if empty
+	Value interface{}
+}
+
+// ResultSet is a result set returned by a stored procedure, []byte values are converted to string.
+type ResultSet struct {
+	Columns []string
+	Rows    [][]interface{}
+}
+
+// CallResult is the result of CallProcedure
+type CallResult struct {
+	Out        []interface{} // values of the parameters after the call, nil for ParamIn ones
+	ResultSets []ResultSet
+}
+
+// CallProcedure call a stored procedure. OUT and INOUT parameters are passed through session variables
+// on a pinned connection and read back after the call. Using current database when param database is empty.
+func CallProcedure(db *sql.DB, database, procedure string, params ...CallParam) (*CallResult, error) {
+	schema, err := objectSchema(db, database, procedure, errEmptyParamRoutine)
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var (
+		placeholders = make([]string, len(params))
+		args         []interface{}
+		outVars      []string
+		outIndex     []int
+	)
+	for i, p := range params {
+		variable := "@_storage_call_" + strconv.Itoa(i)
+		switch strings.ToUpper(strings.Trim(p.Mode, " ")) {
+		case "", ParamIn:
+			placeholders[i] = "?"
+			args = append(args, p.Value)
+			continue
+		case ParamOut:
+			_, err = conn.ExecContext(ctx, "SET "+variable+" = NULL")
+		case ParamInOut:
+			_, err = conn.ExecContext(ctx, "SET "+variable+" = ?", p.Value)
+		default:
+			return nil, errInvalidParamMode
+		}
+		if err != nil {
+			return nil, err
+		}
+		placeholders[i] = variable
+		outVars = append(outVars, variable)
+		outIndex = append(outIndex, i)
+	}
+
+	result := &CallResult{Out: make([]interface{}, len(params))}
+	rows, err := conn.QueryContext(ctx, "CALL "+schema+"("+strings.Join(placeholders, ", ")+")", args...)
+	if err != nil {
+		return nil, err
+	}
+	result.ResultSets, err = readResultSets(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(outVars) == 0 {
+		return result, nil
+	}
+	values := make([]interface{}, len(outVars))
+	dest := make([]interface{}, len(outVars))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err = conn.QueryRowContext(ctx, "SELECT "+strings.Join(outVars, ", ")).Scan(dest...); err != nil {
+		return nil, err
+	}
+	for i, v := range values {
+		result.Out[outIndex[i]] = resultValue(v)
+	}
+	return result, nil
+}
+
+// readResultSets read all result sets of rows, skipping those without column such as the status of CALL
+func readResultSets(rows *sql.Rows) ([]ResultSet, error) {
+	var sets []ResultSet
+	for {
+		columns, err := rows.Columns()
+		if err != nil {
+			return nil, err
+		}
+		set := ResultSet{Columns: columns}
+		for rows.Next() {
+			values := make([]interface{}, len(columns))
+			dest := make([]interface{}, len(columns))
+			for i := range values {
+				dest[i] = &values[i]
+			}
+			if err = rows.Scan(dest...); err != nil {
+				return nil, err
+			}
+			for i, v := range values {
+				values[i] = resultValue(v)
+			}
+			set.Rows = append(set.Rows, values)
+		}
+		if err = rows.Err(); err != nil {
+			return nil, err
+		}
+		if len(columns) > 0 {
+			sets = append(sets, set)
+		}
+		if !rows.NextResultSet() {
+			return sets, rows.Err()
+		}
+	}
+}
+
+// resultValue convert []byte returned by the driver to string
+func resultValue(v interface{}) interface{} {
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+	return v
+}
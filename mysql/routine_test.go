@@ -0,0 +1,54 @@
+package mysql
+
+import (
+	"testing"
+)
+
+func Test_RoutineDefSQL(t *testing.T) {
+	def := RoutineDef{
+		Name: "count_user",
+		Type: "procedure",
+		Params: []RoutineParam{
+			{Name: "min_age", Type: "INT"},
+			{Mode: ParamOut, Name: "total", Type: "BIGINT"},
+		},
+		Comment: "count users",
+		Body:    "SELECT COUNT(0) INTO total FROM user WHERE age >= min_age",
+	}
+	expect := "CREATE PROCEDURE mydb.count_user(IN min_age INT, OUT total BIGINT) COMMENT 'count users' SELECT COUNT(0) INTO total FROM user WHERE age >= min_age"
+	if s, err := def.sql("mydb.count_user"); err != nil || s != expect {
+		t.Errorf("expect %q, got %q, %v", expect, s, err)
+	}
+
+	def = RoutineDef{
+		Name:            "add_one",
+		Type:            RoutineFunction,
+		Params:          []RoutineParam{{Name: "n", Type: "INT"}},
+		Returns:         "INT",
+		Characteristics: "DETERMINISTIC NO SQL",
+		Body:            "RETURN n + 1",
+	}
+	expect = "CREATE FUNCTION mydb.add_one(n INT) RETURNS INT DETERMINISTIC NO SQL RETURN n + 1"
+	if s, err := def.sql("mydb.add_one"); err != nil || s != expect {
+		t.Errorf("expect %q, got %q, %v", expect, s, err)
+	}
+	def.Params[0].Mode = ParamOut
+	if _, err := def.sql("mydb.add_one"); err != errInvalidParamMode {
+		t.Error(err)
+	}
+	def.Params[0].Mode = ""
+	def.Returns = " "
+	if _, err := def.sql("mydb.add_one"); err != errEmptyParamReturns {
+		t.Error(err)
+	}
+}
+
+func Test_routineGrantSQL(t *testing.T) {
+	expect := "GRANT EXECUTE, ALTER ROUTINE ON PROCEDURE mydb.count_user TO 'app'@'%' WITH GRANT OPTION"
+	if s := routineGrantSQL(RoutineProcedure, "mydb.count_user", "app", "%", "Execute,Alter Routine,Grant"); s != expect {
+		t.Errorf("expect %q, got %q", expect, s)
+	}
+	if s := routineGrantSQL(RoutineFunction, "mydb.add_one", "app", "%", "Grant"); s != "" {
+		t.Error(s)
+	}
+}
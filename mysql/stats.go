@@ -0,0 +1,280 @@
+package mysql
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TableStats is the size of a table from information_schema.TABLES.
+// On 8.0 the values are cached for information_schema_stats_expiry seconds, run ANALYZE TABLE to refresh them.
+type TableStats struct {
+	Schema        string
+	Table         string
+	Engine        string
+	Rows          uint64 // estimated for InnoDB
+	AvgRowLength  uint64
+	DataLength    uint64
+	IndexLength   uint64
+	DataFree      uint64
+	Fragmentation float64 // DataFree / (DataLength + IndexLength + DataFree)
+
+	AutoIncrement         uint64 // the next value, 0 if the table has no AUTO_INCREMENT column
+	AutoIncrementColumn   string
+	AutoIncrementType     string  // COLUMN_TYPE, e.g. int(11) unsigned
+	AutoIncrementMax      uint64  // max value of the column type
+	AutoIncrementHeadroom uint64  // values left before AutoIncrementMax
+	AutoIncrementUsage    float64 // used ratio of the column type
+}
+
+// DatabaseStats is the sum of TableStats of a database
+type DatabaseStats struct {
+	Schema        string
+	Tables        int
+	Rows          uint64
+	DataLength    uint64
+	IndexLength   uint64
+	DataFree      uint64
+	Fragmentation float64
+}
+
+// TableGrowth is the growth of a table between the first and last records in a history table
+type TableGrowth struct {
+	Schema           string
+	Table            string
+	From             time.Time
+	To               time.Time
+	RowsPerDay       float64
+	DataBytesPerDay  float64
+	IndexBytesPerDay float64
+}
+
+// ListTableStats get the stats of the base tables in databases, using current database when no database is given.
+func ListTableStats(db *sql.DB, databases ...string) ([]TableStats, error) {
+	databases, err := statsDatabases(db, databases)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.Query(
+		`SELECT t.TABLE_SCHEMA, t.TABLE_NAME, COALESCE(t.ENGINE, ''), COALESCE(t.TABLE_ROWS, 0), 
+			COALESCE(t.AVG_ROW_LENGTH, 0), COALESCE(t.DATA_LENGTH, 0), COALESCE(t.INDEX_LENGTH, 0), 
+			COALESCE(t.DATA_FREE, 0), COALESCE(t.AUTO_INCREMENT, 0), COALESCE(c.COLUMN_NAME, ''), COALESCE(c.COLUMN_TYPE, '') 
+			FROM information_schema.TABLES t 
+			LEFT JOIN information_schema.COLUMNS c ON c.TABLE_SCHEMA = t.TABLE_SCHEMA AND c.TABLE_NAME = t.TABLE_NAME 
+				AND c.EXTRA LIKE '%auto_increment%' 
+			WHERE t.TABLE_TYPE = 'BASE TABLE' AND t.TABLE_SCHEMA IN (?`+strings.Repeat(", ?", len(databases)-1)+`) 
+			ORDER BY t.TABLE_SCHEMA, t.TABLE_NAME`, stringArgs(databases)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []TableStats
+	for rows.Next() {
+		var s TableStats
+		err = rows.Scan(&s.Schema, &s.Table, &s.Engine, &s.Rows, &s.AvgRowLength, &s.DataLength, &s.IndexLength,
+			&s.DataFree, &s.AutoIncrement, &s.AutoIncrementColumn, &s.AutoIncrementType,
+		)
+		if err != nil {
+			return nil, err
+		}
+		s.Fragmentation = fragmentation(s.DataLength, s.IndexLength, s.DataFree)
+		if s.AutoIncrementColumn == "" {
+			s.AutoIncrement = 0
+		} else if max, ok := columnMaxValue(s.AutoIncrementType); ok {
+			s.AutoIncrementMax = max
+			s.AutoIncrementHeadroom, s.AutoIncrementUsage = autoIncrementUsage(s.AutoIncrement, max)
+		}
+		stats = append(stats, s)
+	}
+	return stats, rows.Err()
+}
+
+// AggregateTableStats sum stats per database in the order the databases first appear
+func AggregateTableStats(stats []TableStats) []DatabaseStats {
+	var aggregated []DatabaseStats
+	index := make(map[string]int)
+	for _, s := range stats {
+		i, ok := index[s.Schema]
+		if !ok {
+			i = len(aggregated)
+			index[s.Schema] = i
+			aggregated = append(aggregated, DatabaseStats{Schema: s.Schema})
+		}
+		d := &aggregated[i]
+		d.Tables++
+		d.Rows += s.Rows
+		d.DataLength += s.DataLength
+		d.IndexLength += s.IndexLength
+		d.DataFree += s.DataFree
+	}
+	for i := range aggregated {
+		d := &aggregated[i]
+		d.Fragmentation = fragmentation(d.DataLength, d.IndexLength, d.DataFree)
+	}
+	return aggregated
+}
+
+// fragmentation get the ratio of free space in the tablespace
+func fragmentation(data, index, free uint64) float64 {
+	if total := data + index + free; total > 0 {
+		return float64(free) / float64(total)
+	}
+	return 0
+}
+
+// autoIncrementUsage get the values left and the used ratio of an AUTO_INCREMENT column, next is the next value
+func autoIncrementUsage(next, max uint64) (headroom uint64, usage float64) {
+	if next == 0 || max == 0 {
+		return max, 0
+	}
+	used := next - 1
+	if used >= max {
+		return 0, 1
+	}
+	return max - used, float64(used) / float64(max)
+}
+
+// columnMaxValue get the max value of an integer column type such as int(11) unsigned or bigint
+func columnMaxValue(columnType string) (uint64, bool) {
+	columnType = strings.ToLower(strings.TrimSpace(columnType))
+	base := columnType
+	if i := strings.IndexAny(base, "( "); i >= 0 {
+		base = base[:i]
+	}
+	unsigned := strings.Contains(columnType, "unsigned")
+	var bits uint
+	switch base {
+	case "tinyint":
+		bits = 8
+	case "smallint":
+		bits = 16
+	case "mediumint":
+		bits = 24
+	case "int", "integer":
+		bits = 32
+	case "bigint":
+		bits = 64
+	default:
+		return 0, false
+	}
+	if unsigned {
+		return 1<<(bits-1)<<1 - 1, true
+	}
+	return 1<<(bits-1) - 1, true
+}
+
+// statsDatabases trim databases, or get current database if there is none
+func statsDatabases(db *sql.DB, databases []string) ([]string, error) {
+	var trimmed []string
+	for _, database := range databases {
+		if database = strings.Trim(database, " "); database != "" {
+			trimmed = append(trimmed, database)
+		}
+	}
+	if len(trimmed) > 0 {
+		return trimmed, nil
+	}
+	database, err := getDatabaseName(db)
+	if err != nil {
+		return nil, err
+	}
+	return []string{database}, nil
+}
+
+// tableStatsHistoryColumns are the columns of the history table of RecordTableStats
+var tableStatsHistoryColumns = []ColumnDef{
+	{Name: "id", Type: "BIGINT UNSIGNED", PrimaryKey: true, AutoIncrement: true, NotNull: true},
+	{Name: "recorded_at", Type: "DATETIME", NotNull: true},
+	{Name: "table_schema", Type: "VARCHAR(64)", NotNull: true},
+	{Name: "table_name", Type: "VARCHAR(64)", NotNull: true},
+	{Name: "table_rows", Type: "BIGINT UNSIGNED", NotNull: true},
+	{Name: "data_length", Type: "BIGINT UNSIGNED", NotNull: true},
+	{Name: "index_length", Type: "BIGINT UNSIGNED", NotNull: true},
+	{Name: "data_free", Type: "BIGINT UNSIGNED", NotNull: true},
+}
+
+// RecordTableStats insert the current stats of the tables in databases into table history, which is created if not exists.
+// Using current database when no database is given.
+func RecordTableStats(db *sql.DB, history string, databases ...string) error {
+	database, table, err := parseTableSchema(db, history)
+	if err != nil {
+		return err
+	}
+	history = database + "." + table
+	if databases, err = statsDatabases(db, databases); err != nil {
+		return err
+	}
+	var query string
+	if query, err = tableSQL(history, tableStatsHistoryColumns, "INDEX(table_schema, table_name, recorded_at)"); err != nil {
+		return err
+	}
+	if _, err = db.Exec(query); err != nil {
+		return err
+	}
+	_, err = db.Exec(
+		`INSERT INTO `+history+`(recorded_at, table_schema, table_name, table_rows, data_length, index_length, data_free) 
+			SELECT NOW(), TABLE_SCHEMA, TABLE_NAME, COALESCE(TABLE_ROWS, 0), COALESCE(DATA_LENGTH, 0), 
+				COALESCE(INDEX_LENGTH, 0), COALESCE(DATA_FREE, 0) 
+			FROM information_schema.TABLES 
+			WHERE TABLE_TYPE = 'BASE TABLE' AND TABLE_SCHEMA IN (?`+strings.Repeat(", ?", len(databases)-1)+`)`,
+		stringArgs(databases)...,
+	)
+	return err
+}
+
+// GetTableGrowth compute the growth per day of table schema from the first and last records within since in table history.
+// Use the currently selected database if schema or history does not contain database.
+func GetTableGrowth(db *sql.DB, history, schema string, since time.Duration) (*TableGrowth, error) {
+	historyDatabase, historyTable, err := parseTableSchema(db, history)
+	if err != nil {
+		return nil, err
+	}
+	database, table, err := parseTableSchema(db, schema)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.Query(
+		`SELECT UNIX_TIMESTAMP(recorded_at), table_rows, data_length, index_length 
+			FROM `+historyDatabase+"."+historyTable+` 
+			WHERE table_schema = ? AND table_name = ? 
+				AND recorded_at >= NOW() - INTERVAL `+strconv.FormatInt(int64(since/time.Second), 10)+` SECOND 
+			ORDER BY recorded_at`, database, table,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type record struct {
+		at                int64
+		rows, data, index float64
+	}
+	var first, last *record
+	for rows.Next() {
+		r := &record{}
+		if err = rows.Scan(&r.at, &r.rows, &r.data, &r.index); err != nil {
+			return nil, err
+		}
+		if first == nil {
+			first = r
+		}
+		last = r
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	g := &TableGrowth{Schema: database, Table: table}
+	if first == nil {
+		return g, nil
+	}
+	g.From, g.To = time.Unix(first.at, 0), time.Unix(last.at, 0)
+	if days := g.To.Sub(g.From).Hours() / 24; days > 0 {
+		g.RowsPerDay = (last.rows - first.rows) / days
+		g.DataBytesPerDay = (last.data - first.data) / days
+		g.IndexBytesPerDay = (last.index - first.index) / days
+	}
+	return g, nil
+}
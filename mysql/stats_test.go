@@ -0,0 +1,48 @@
+package mysql
+
+import (
+	"testing"
+)
+
+func Test_columnMaxValue(t *testing.T) {
+	cases := map[string]uint64{
+		"tinyint(4)":          127,
+		"tinyint(3) unsigned": 255,
+		"smallint":            32767,
+		"mediumint unsigned":  16777215,
+		"int(11)":             2147483647,
+		"int(10) unsigned":    4294967295,
+		"bigint(20)":          9223372036854775807,
+		"bigint unsigned":     18446744073709551615,
+	}
+	for columnType, expect := range cases {
+		if max, ok := columnMaxValue(columnType); !ok || max != expect {
+			t.Error(columnType, max, ok)
+		}
+	}
+	if _, ok := columnMaxValue("decimal(10,0)"); ok {
+		t.Error("expect not integer")
+	}
+
+	if headroom, usage := autoIncrementUsage(101, 200); headroom != 100 || usage != 0.5 {
+		t.Error(headroom, usage)
+	}
+	if headroom, usage := autoIncrementUsage(256, 255); headroom != 0 || usage != 1 {
+		t.Error(headroom, usage)
+	}
+}
+
+func Test_AggregateTableStats(t *testing.T) {
+	aggregated := AggregateTableStats([]TableStats{
+		{Schema: "a", Table: "t1", Rows: 10, DataLength: 100, IndexLength: 50, DataFree: 50},
+		{Schema: "b", Table: "t1", Rows: 1, DataLength: 10},
+		{Schema: "a", Table: "t2", Rows: 5, DataLength: 100, IndexLength: 100, DataFree: 0},
+	})
+	if len(aggregated) != 2 {
+		t.Fatal(aggregated)
+	}
+	a := aggregated[0]
+	if a.Schema != "a" || a.Tables != 2 || a.Rows != 15 || a.DataLength != 200 || a.IndexLength != 150 || a.Fragmentation != 0.125 {
+		t.Error(a)
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // TableExist check whether a table exists
@@ -96,48 +98,52 @@ func CreateTableWithSchemaIfNotExist(db *sql.DB, i interface{}, schema string) e
 	if t.NumField() == 0 {
 		return errors.New("struct has no field")
 	}
-	sqlTable := getTableSQL(schema, t)
+	sqlTable, err := getTableSQL(schema, t)
+	if err != nil {
+		return err
+	}
 	_, err = db.Exec(sqlTable)
 	return err
 }
 
 // getTableSQL get the SQL for create a table
-func getTableSQL(schema string, t reflect.Type) string {
-	sqlColumns := getColumnsSQL(t)
+func getTableSQL(schema string, t reflect.Type) (string, error) {
+	return tableSQL(schema, getColumnDefs(t))
+}
+
+// tableSQL get the SQL for create a table with columns defs and optional index definitions
+func tableSQL(schema string, defs []ColumnDef, indexes ...string) (string, error) {
 	sqlTable := "CREATE TABLE IF NOT EXISTS " + schema + "("
-	for i, c := range sqlColumns {
+	for i, c := range defs {
+		column, err := c.sql(false)
+		if err != nil {
+			return "", err
+		}
 		if i == 0 {
-			sqlTable = sqlTable + c
+			sqlTable = sqlTable + column
 		} else {
-			sqlTable = sqlTable + "," + c
+			sqlTable = sqlTable + "," + column
 		}
 	}
-	return sqlTable + ");"
+	for _, index := range indexes {
+		sqlTable = sqlTable + "," + index
+	}
+	return sqlTable + ");", nil
 }
 
-// getColumnsSQL create the columns part of SQL for create a table
-func getColumnsSQL(t reflect.Type) (sqlColumns []string) {
+// getColumnDefs create the column definitions for create a table
+func getColumnDefs(t reflect.Type) (defs []ColumnDef) {
 	n := t.NumField()
 	for i := 0; i < n; i++ {
 		field := t.Field(i)
 		if field.Anonymous && field.Type.Kind() == reflect.Struct {
-			sqlSubColumns := getColumnsSQL(field.Type)
-			sqlColumns = append(sqlColumns, sqlSubColumns...)
+			defs = append(defs, getColumnDefs(field.Type)...)
 		} else {
-			var (
-				sqlColumn       string
-				columnName      string
-				columnType      string
-				isPrimaryKey    bool
-				isAutoIncrement bool
-				isUnique        bool
-				isNotNull       bool
-				columnDefault   string
-			)
+			var def ColumnDef
 			args := strings.Split(strings.Replace(field.Tag.Get("mysql"), " ", "", -1), ",")
-			columnName = args[0]
-			if columnName == "" {
-				columnName = strings.ToLower(field.Name)
+			def.Name = args[0]
+			if def.Name == "" {
+				def.Name = strings.ToLower(field.Name)
 			}
 			var fieldType reflect.Type
 			if field.Type.Kind() == reflect.Ptr {
@@ -148,19 +154,19 @@ func getColumnsSQL(t reflect.Type) (sqlColumns []string) {
 
 			switch fieldType.Kind() {
 			case reflect.Bool:
-				columnType = "TINYINT"
+				def.Type = "TINYINT"
 			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-				columnType = "INT"
+				def.Type = "INT"
 			case reflect.Float32:
-				columnType = "FLOAT"
+				def.Type = "FLOAT"
 			case reflect.Float64:
-				columnType = "DOUBLE"
+				def.Type = "DOUBLE"
 			case reflect.String:
-				columnType = "VARCHAR"
+				def.Type = "VARCHAR"
 			default:
 				ft := fieldType.String()
 				if ft == "time.Time" {
-					columnType = "DATETIME"
+					def.Type = "DATETIME"
 				} else {
 					panic(fmt.Sprintf("unsuported type for mysql %s", ft))
 				}
@@ -183,47 +189,37 @@ func getColumnsSQL(t reflect.Type) (sqlColumns []string) {
 				switch argSplited[0] {
 				case "size":
 					// after columnType, means size when columnType=VARCHAR
-					columnType = columnType + "(" + argSplited[1] + ")"
+					def.Type = def.Type + "(" + argSplited[1] + ")"
 				case "default":
-					columnDefault = "DEFAULT " + parseDefault(columnType, argSplited[1])
+					def.Default = parseDefault(argSplited[1])
 				case "primarykey":
-					isPrimaryKey = true
+					def.PrimaryKey = true
 				case "autoincrement":
-					isAutoIncrement = true
+					def.AutoIncrement = true
 				case "unique":
-					isUnique = true
+					def.Unique = true
 				case "notnull":
-					isNotNull = true
+					def.NotNull = true
 				default:
 					panic(fmt.Sprintf("Unrecognized tag option for field %v: %v", field.Name, argSplited[0]))
 				}
 			}
-			sqlColumn = columnName + " " + columnType
-			if isPrimaryKey {
-				sqlColumn = sqlColumn + " PRIMARY KEY"
-			}
-			if isAutoIncrement {
-				sqlColumn = sqlColumn + " AUTO_INCREMENT"
-			}
-			if isUnique {
-				sqlColumn = sqlColumn + " UNIQUE"
-			}
-			if isNotNull {
-				sqlColumn = sqlColumn + " NOT NULL"
-			}
-			if columnDefault != "" {
-				sqlColumn = sqlColumn + " " + columnDefault
-			}
-			sqlColumns = append(sqlColumns, sqlColumn)
+			defs = append(defs, def)
 		}
 	}
 	return
 }
 
-// DropTable drop a specific table
+// DropTable drop a specific table, guarded by DefaultDropPolicy
 // Panic if schema does not contain table
 // Return errDropTableNotExist when table does not exists
 func DropTable(db *sql.DB, schema string) error {
+	return DropTableWithPolicy(db, schema, &DefaultDropPolicy)
+}
+
+// DropTableWithPolicy drop a specific table if p allows it
+// Return errDropTableNotExist when table does not exists
+func DropTableWithPolicy(db *sql.DB, schema string, p *DropPolicy) error {
 	database, table, err := parseTableSchema(db, schema)
 	if err != nil {
 		return err
@@ -237,17 +233,250 @@ func DropTable(db *sql.DB, schema string) error {
 	if !isexist {
 		return errDropedTableNotExist
 	}
-	_, err = db.Exec("DROP TABLE " + schema)
-	return err
+	if err = p.checkTable(db, database, table, schema); err != nil {
+		return err
+	}
+	if p.RecycleBin != "" {
+		return p.moveToRecycleBin(db, database, table)
+	}
+	return p.exec(db, "DROP TABLE "+schema)
 }
 
-// DropTableIfExist drop a specific table if exists
+// DropTableIfExist drop a specific table if exists, guarded by DefaultDropPolicy
 // Panic if schema does not contain table
 func DropTableIfExist(db *sql.DB, schema string) error {
+	return DropTableIfExistWithPolicy(db, schema, &DefaultDropPolicy)
+}
+
+// DropTableIfExistWithPolicy drop a specific table if exists and p allows it
+func DropTableIfExistWithPolicy(db *sql.DB, schema string, p *DropPolicy) error {
+	database, table, err := parseTableSchema(db, schema)
+	if err != nil {
+		return err
+	}
+	schema = database + "." + table
+	var isexist bool
+	isexist, err = TableExist(db, schema)
+	if err != nil {
+		return err
+	}
+	if !isexist {
+		return nil
+	}
+	if err = p.checkTable(db, database, table, schema); err != nil {
+		return err
+	}
+	if p.RecycleBin != "" {
+		return p.moveToRecycleBin(db, database, table)
+	}
+	return p.exec(db, "DROP TABLE IF EXISTS "+schema)
+}
+
+// RenameTable rename table from to table to, to may be in another database.
+// Use the currently selected database if from or to does not contain database.
+func RenameTable(db *sql.DB, from, to string) error {
+	fromDatabase, fromTable, err := parseTableSchema(db, from)
+	if err != nil {
+		return err
+	}
+	toDatabase, toTable, err := parseTableSchema(db, to)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec("RENAME TABLE " + fromDatabase + "." + fromTable + " TO " + toDatabase + "." + toTable)
+	return err
+}
+
+// SwapTables exchange the names of table a and table b in a single atomic RENAME TABLE.
+func SwapTables(db *sql.DB, a, b string) error {
+	aDatabase, aTable, err := parseTableSchema(db, a)
+	if err != nil {
+		return err
+	}
+	bDatabase, bTable, err := parseTableSchema(db, b)
+	if err != nil {
+		return err
+	}
+	a, b = aDatabase+"."+aTable, bDatabase+"."+bTable
+	tmp := aDatabase + "." + tempTableName(aTable, "swap")
+	_, err = db.Exec("RENAME TABLE " + a + " TO " + tmp + ", " + b + " TO " + a + ", " + tmp + " TO " + b)
+	return err
+}
+
+// TruncateTableIfExist truncate a specific table if exists
+func TruncateTableIfExist(db *sql.DB, schema string) error {
+	database, table, err := parseTableSchema(db, schema)
+	if err != nil {
+		return err
+	}
+	schema = database + "." + table
+	var isexist bool
+	isexist, err = TableExist(db, schema)
+	if err != nil {
+		return err
+	}
+	if !isexist {
+		return nil
+	}
+	_, err = db.Exec("TRUNCATE TABLE " + schema)
+	return err
+}
+
+// CreateTableLike create an empty table with the same structure as table like,
+// return errTableAlreadyExist if the table is already exist.
+func CreateTableLike(db *sql.DB, schema, like string) error {
 	database, table, err := parseTableSchema(db, schema)
 	if err != nil {
 		return err
 	}
-	_, err = db.Exec("DROP TABLE IF EXISTS " + database + "." + table)
+	likeDatabase, likeTable, err := parseTableSchema(db, like)
+	if err != nil {
+		return err
+	}
+	schema = database + "." + table
+	var isexist bool
+	isexist, err = TableExist(db, schema)
+	if err != nil {
+		return err
+	}
+	if isexist {
+		return errTableAlreadyExist
+	}
+	_, err = db.Exec("CREATE TABLE " + schema + " LIKE " + likeDatabase + "." + likeTable)
 	return err
 }
+
+// CopyTableOptions controls the data copied by CopyTable.
+type CopyTableOptions struct {
+	Data      bool   // copy rows as well as structure
+	Where     string // optional filter of copied rows, without the WHERE keyword
+	ChunkSize int    // copy rows in chunks ordered by primary key, copy in one statement if 0
+}
+
+// CopyTable create table dst with the structure of table src, and copy its rows if opts.Data is true.
+// Return errTableAlreadyExist if dst is already exist. A nil opts copies structure only.
+// Chunked copy requires src to have a single column primary key.
+func CopyTable(db *sql.DB, src, dst string, opts *CopyTableOptions) error {
+	srcDatabase, srcTable, err := parseTableSchema(db, src)
+	if err != nil {
+		return err
+	}
+	dstDatabase, dstTable, err := parseTableSchema(db, dst)
+	if err != nil {
+		return err
+	}
+	src, dst = srcDatabase+"."+srcTable, dstDatabase+"."+dstTable
+	if err = CreateTableLike(db, dst, src); err != nil {
+		return err
+	}
+	if opts == nil || !opts.Data {
+		return nil
+	}
+	where := strings.Trim(opts.Where, " ")
+	if where != "" {
+		where = "(" + where + ")"
+	}
+	if opts.ChunkSize <= 0 {
+		query := "INSERT INTO " + dst + " SELECT * FROM " + src
+		if where != "" {
+			query += " WHERE " + where
+		}
+		_, err = db.Exec(query)
+		return err
+	}
+
+	var pk, pkType string
+	pk, pkType, err = singlePrimaryKey(db, srcDatabase, srcTable)
+	if err != nil {
+		return err
+	}
+	if where == "" {
+		where = "1=1"
+	}
+	var last []byte
+	for {
+		// upper bound of the next chunk, scanned as text so that integer keys keep their precision
+		var upper []byte
+		query := "SELECT MAX(" + pk + ") FROM (SELECT " + pk + " FROM " + src + " WHERE " + where
+		var args []interface{}
+		if last != nil {
+			bound, arg := chunkBound(pkType, last)
+			query += " AND " + pk + " > " + bound
+			args = append(args, arg...)
+		}
+		query += " ORDER BY " + pk + " LIMIT " + strconv.Itoa(opts.ChunkSize) + ") chunk"
+		if err = db.QueryRow(query, args...).Scan(&upper); err != nil {
+			return err
+		}
+		if upper == nil {
+			return nil
+		}
+
+		bound, arg := chunkBound(pkType, upper)
+		query = "INSERT INTO " + dst + " SELECT * FROM " + src + " WHERE " + where + " AND " + pk + " <= " + bound
+		args = arg
+		if last != nil {
+			bound, arg = chunkBound(pkType, last)
+			query += " AND " + pk + " > " + bound
+			args = append(args, arg...)
+		}
+		if _, err = db.Exec(query, args...); err != nil {
+			return err
+		}
+		last = upper
+	}
+}
+
+// chunkBound render a bound of a chunk on a primary key of type columnType. Numeric bounds are written as is,
+// because a string argument would be compared with the column as a double and lose precision above 2^53.
+// Other bounds are passed as an argument.
+func chunkBound(columnType string, value []byte) (string, []interface{}) {
+	if isNumericType(columnType) && !strings.HasPrefix(strings.ToUpper(strings.Trim(columnType, " ")), "BIT") {
+		if _, err := strconv.ParseFloat(string(value), 64); err == nil {
+			return string(value), nil
+		}
+	}
+	return "?", []interface{}{string(value)}
+}
+
+// singlePrimaryKey get the primary key column of database.table and its type, return errNoSinglePrimaryKey
+// if the table has no primary key or a composite one.
+func singlePrimaryKey(db *sql.DB, database, table string) (string, string, error) {
+	rows, err := db.Query(
+		`SELECT s.COLUMN_NAME, c.COLUMN_TYPE 
+			FROM information_schema.STATISTICS s 
+			JOIN information_schema.COLUMNS c ON c.TABLE_SCHEMA = s.TABLE_SCHEMA AND c.TABLE_NAME = s.TABLE_NAME 
+				AND c.COLUMN_NAME = s.COLUMN_NAME 
+			WHERE s.TABLE_SCHEMA = ? AND s.TABLE_NAME = ? AND s.INDEX_NAME = 'PRIMARY'`, database, table,
+	)
+	if err != nil {
+		return "", "", err
+	}
+	defer rows.Close()
+
+	var columns, types []string
+	for rows.Next() {
+		var column, columnType string
+		if err = rows.Scan(&column, &columnType); err != nil {
+			return "", "", err
+		}
+		columns = append(columns, column)
+		types = append(types, columnType)
+	}
+	if err = rows.Err(); err != nil {
+		return "", "", err
+	}
+	if len(columns) != 1 {
+		return "", "", errNoSinglePrimaryKey
+	}
+	return columns[0], types[0], nil
+}
+
+// tempTableName build a name from table and a time based suffix, which is never longer than 64 characters.
+func tempTableName(table, tag string) string {
+	suffix := "_" + tag + "_" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	if len(table)+len(suffix) > 64 {
+		table = table[:64-len(suffix)]
+	}
+	return table + suffix
+}
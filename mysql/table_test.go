@@ -2,6 +2,7 @@ package mysql
 
 import (
 	"database/sql"
+	"strings"
 	"testing"
 	"time"
 
@@ -78,3 +79,28 @@ func Test_table(t *testing.T) {
 	// 	t.Error(err)
 	// }
 }
+
+func Test_tempTableName(t *testing.T) {
+	long := strings.Repeat("t", 64)
+	if name := tempTableName(long, "swap"); len(name) != 64 || !strings.Contains(name, "_swap_") {
+		t.Error(name)
+	}
+	if name := tempTableName("user", "swap"); !strings.HasPrefix(name, "user_swap_") {
+		t.Error(name)
+	}
+}
+
+func Test_chunkBound(t *testing.T) {
+	if bound, args := chunkBound("bigint(20) unsigned", []byte("18446744073709551615")); bound != "18446744073709551615" || args != nil {
+		t.Error(bound, args)
+	}
+	if bound, args := chunkBound("decimal(30,0)", []byte("9007199254740993")); bound != "9007199254740993" || args != nil {
+		t.Error(bound, args)
+	}
+	if bound, args := chunkBound("varchar(32)", []byte("9007199254740993")); bound != "?" || len(args) != 1 || args[0] != "9007199254740993" {
+		t.Error(bound, args)
+	}
+	if bound, args := chunkBound("bit(8)", []byte{0xff}); bound != "?" || len(args) != 1 {
+		t.Error(bound, args)
+	}
+}
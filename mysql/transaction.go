@@ -2,10 +2,96 @@ package mysql
 
 import (
 	"database/sql"
+	"time"
+
+	gomysql "github.com/go-sql-driver/mysql"
 )
 
+// errNoSuchThread is the error number of ER_NO_SUCH_THREAD
+const errNoSuchThread = 1094
+
 // NumTransaction return the number of transaction
 func NumTransaction(db *sql.DB) (num int, err error) {
 	err = db.QueryRow("SELECT COUNT(0) FROM information_schema.INNODB_TRX").Scan(&num)
 	return
 }
+
+// Transaction is a running InnoDB transaction with the connection owning it
+type Transaction struct {
+	ID             string // trx_id
+	State          string // RUNNING | LOCK WAIT | ROLLING BACK | COMMITTING
+	Started        *time.Time
+	Age            time.Duration
+	RowsLocked     uint64
+	RowsModified   uint64
+	IsolationLevel string
+	Thread         uint64 // ID of the connection in PROCESSLIST
+	User           string
+	Host           string
+	Command        string // Sleep while the client holds the transaction open without running a statement
+	Query          string // the statement being executed, empty if none
+}
+
+// ListTransactions get the running InnoDB transactions, the oldest first.
+func ListTransactions(db *sql.DB) ([]Transaction, error) {
+	return listTransactions(db, 0)
+}
+
+// listTransactions get the transactions running for at least minAge
+func listTransactions(db *sql.DB, minAge time.Duration) ([]Transaction, error) {
+	rows, err := db.Query(
+		`SELECT t.trx_id, t.trx_state, t.trx_started, TIMESTAMPDIFF(SECOND, t.trx_started, NOW()), 
+			t.trx_rows_locked, t.trx_rows_modified, t.trx_isolation_level, t.trx_mysql_thread_id, 
+			COALESCE(p.USER, ''), COALESCE(p.HOST, ''), COALESCE(p.COMMAND, ''), COALESCE(t.trx_query, p.INFO, '') 
+			FROM information_schema.INNODB_TRX t 
+			LEFT JOIN information_schema.PROCESSLIST p ON p.ID = t.trx_mysql_thread_id 
+			WHERE t.trx_started <= NOW() - INTERVAL ? SECOND 
+			ORDER BY t.trx_started`, int64(minAge/time.Second),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactions []Transaction
+	for rows.Next() {
+		var (
+			t       Transaction
+			seconds int64
+		)
+		err = rows.Scan(&t.ID, &t.State, nullTime{&t.Started}, &seconds,
+			&t.RowsLocked, &t.RowsModified, &t.IsolationLevel, &t.Thread,
+			&t.User, &t.Host, &t.Command, &t.Query,
+		)
+		if err != nil {
+			return nil, err
+		}
+		t.Age = time.Duration(seconds) * time.Second
+		transactions = append(transactions, t)
+	}
+	return transactions, rows.Err()
+}
+
+// LongTransactions get the transactions running for at least threshold, and kill their connections if kill is true.
+// The transactions are rolled back when their connections are killed, those which ended before being killed are skipped.
+func LongTransactions(db *sql.DB, threshold time.Duration, kill bool) ([]Transaction, error) {
+	transactions, err := listTransactions(db, threshold)
+	if err != nil || !kill {
+		return transactions, err
+	}
+	for _, t := range transactions {
+		if t.Thread == 0 {
+			continue
+		}
+		if err = KillConnection(db, t.Thread); err != nil && !isNoSuchThread(err) {
+			return transactions, err
+		}
+	}
+	return transactions, nil
+}
+
+// isNoSuchThread report whether err is ER_NO_SUCH_THREAD, returned when killing a connection which has gone
+func isNoSuchThread(err error) bool {
+	e, ok := err.(*gomysql.MySQLError)
+	return ok && e.Number == errNoSuchThread
+}
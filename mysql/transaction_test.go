@@ -0,0 +1,17 @@
+package mysql
+
+import (
+	"errors"
+	"testing"
+
+	gomysql "github.com/go-sql-driver/mysql"
+)
+
+func Test_isNoSuchThread(t *testing.T) {
+	if !isNoSuchThread(&gomysql.MySQLError{Number: 1094, Message: "Unknown thread id: 42"}) {
+		t.Error("expect no such thread")
+	}
+	if isNoSuchThread(&gomysql.MySQLError{Number: 1205}) || isNoSuchThread(errors.New("1094")) {
+		t.Error("expect other error")
+	}
+}
@@ -0,0 +1,195 @@
+package mysql
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// Trigger timings
+const (
+	TriggerTimingBefore = "BEFORE"
+	TriggerTimingAfter  = "AFTER"
+)
+
+// Trigger events
+const (
+	TriggerEventInsert = "INSERT"
+	TriggerEventUpdate = "UPDATE"
+	TriggerEventDelete = "DELETE"
+)
+
+// triggerColumns are the columns scanned into DescTrigger
+const triggerColumns = `TRIGGER_SCHEMA, TRIGGER_NAME, EVENT_MANIPULATION, EVENT_OBJECT_SCHEMA, EVENT_OBJECT_TABLE, 
+	ACTION_ORDER, ACTION_STATEMENT, ACTION_TIMING, CREATED, SQL_MODE, DEFINER, 
+	CHARACTER_SET_CLIENT, COLLATION_CONNECTION, DATABASE_COLLATION`
+
+// TriggerDef describes a trigger for CreateTriggerIfNotExist
+type TriggerDef struct {
+	Name     string
+	Timing   string // TriggerTimingBefore | TriggerTimingAfter
+	Event    string // TriggerEventInsert | TriggerEventUpdate | TriggerEventDelete
+	Body     string // a statement, or BEGIN ... END
+	Follows  string // optional, activate after this trigger with the same timing and event
+	Precedes string // optional, activate before this trigger with the same timing and event
+}
+
+// DescTrigger is the detail information of a trigger
+type DescTrigger struct {
+	Schema            string
+	Name              string
+	Event             string // INSERT | UPDATE | DELETE
+	TableSchema       string
+	Table             string
+	Order             int64 // position among triggers on the same table with the same event and timing
+	Statement         string
+	Timing            string // BEFORE | AFTER
+	Created           *time.Time
+	SQLMode           string
+	Definer           string
+	Charset           string // character_set_client when the trigger was created
+	Collation         string // collation_connection when the trigger was created
+	DatabaseCollation string
+}
+
+// scanTrigger scan a row selected with triggerColumns
+func scanTrigger(s scanner) (*DescTrigger, error) {
+	dt := &DescTrigger{}
+	err := s.Scan(&dt.Schema, &dt.Name, &dt.Event, &dt.TableSchema, &dt.Table,
+		&dt.Order, &dt.Statement, &dt.Timing, nullTime{&dt.Created}, &dt.SQLMode, &dt.Definer,
+		&dt.Charset, &dt.Collation, &dt.DatabaseCollation,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+// TriggerExist check wheather database.trigger exist. Using current database when param database is empty.
+func TriggerExist(db *sql.DB, database, trigger string) (bool, error) {
+	schema, err := objectSchema(db, database, trigger, errEmptyParamTrigger)
+	if err != nil {
+		return false, err
+	}
+	database, trigger = splitSchema(schema)
+	r := db.QueryRow(
+		`SELECT TRIGGER_NAME 
+			FROM information_schema.TRIGGERS 
+			WHERE TRIGGER_SCHEMA = ? AND TRIGGER_NAME = ?`, database, trigger,
+	)
+	return exist(r)
+}
+
+// DescribeTrigger get the detail information of trigger. Using current database when param database is empty.
+func DescribeTrigger(db *sql.DB, database, trigger string) (*DescTrigger, error) {
+	schema, err := objectSchema(db, database, trigger, errEmptyParamTrigger)
+	if err != nil {
+		return nil, err
+	}
+	database, trigger = splitSchema(schema)
+	var dt *DescTrigger
+	dt, err = scanTrigger(db.QueryRow(
+		`SELECT `+triggerColumns+` 
+			FROM information_schema.TRIGGERS 
+			WHERE TRIGGER_SCHEMA = ? AND TRIGGER_NAME = ?`, database, trigger,
+	))
+	if err == sql.ErrNoRows {
+		return nil, errTriggerNotExist
+	}
+	return dt, err
+}
+
+// ListTriggers get the triggers on a table ordered by event, timing and order of activation.
+// Use the currently selected database if schema does not contain database.
+func ListTriggers(db *sql.DB, schema string) ([]*DescTrigger, error) {
+	database, table, err := parseTableSchema(db, schema)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.Query(
+		`SELECT `+triggerColumns+` 
+			FROM information_schema.TRIGGERS 
+			WHERE EVENT_OBJECT_SCHEMA = ? AND EVENT_OBJECT_TABLE = ? 
+			ORDER BY EVENT_MANIPULATION, ACTION_TIMING, ACTION_ORDER`, database, table,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var triggers []*DescTrigger
+	for rows.Next() {
+		var dt *DescTrigger
+		if dt, err = scanTrigger(rows); err != nil {
+			return nil, err
+		}
+		triggers = append(triggers, dt)
+	}
+	return triggers, rows.Err()
+}
+
+// CreateTriggerIfNotExist create a trigger on a table if not exists, the trigger is in the database of the table.
+// Use the currently selected database if schema does not contain database.
+func CreateTriggerIfNotExist(db *sql.DB, schema string, def TriggerDef) error {
+	database, table, err := parseTableSchema(db, schema)
+	if err != nil {
+		return err
+	}
+	var query string
+	if query, err = def.sql(database + "." + table); err != nil {
+		return err
+	}
+	var isexist bool
+	isexist, err = TriggerExist(db, database, def.Name)
+	if err != nil {
+		return err
+	}
+	if isexist {
+		return nil
+	}
+	_, err = db.Exec(query)
+	return err
+}
+
+// sql renders CREATE TRIGGER on table schema
+func (def *TriggerDef) sql(schema string) (string, error) {
+	name := strings.Trim(def.Name, " ")
+	if name == "" {
+		return "", errEmptyParamTrigger
+	}
+	timing := strings.ToUpper(strings.Trim(def.Timing, " "))
+	if timing != TriggerTimingBefore && timing != TriggerTimingAfter {
+		return "", errInvalidTriggerTiming
+	}
+	event := strings.ToUpper(strings.Trim(def.Event, " "))
+	if event != TriggerEventInsert && event != TriggerEventUpdate && event != TriggerEventDelete {
+		return "", errInvalidTriggerEvent
+	}
+	body := strings.Trim(def.Body, " ")
+	if body == "" {
+		return "", errEmptyParamBody
+	}
+	follows, precedes := strings.Trim(def.Follows, " "), strings.Trim(def.Precedes, " ")
+	if follows != "" && precedes != "" {
+		return "", errTriggerOrderConflict
+	}
+
+	database, _ := splitSchema(schema)
+	query := "CREATE TRIGGER " + database + "." + name + " " + timing + " " + event + " ON " + schema + " FOR EACH ROW"
+	if follows != "" {
+		query += " FOLLOWS " + follows
+	} else if precedes != "" {
+		query += " PRECEDES " + precedes
+	}
+	return query + " " + body, nil
+}
+
+// DropTriggerIfExist drop a trigger if exists. Using current database when param database is empty.
+func DropTriggerIfExist(db *sql.DB, database, trigger string) error {
+	schema, err := objectSchema(db, database, trigger, errEmptyParamTrigger)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec("DROP TRIGGER IF EXISTS " + schema)
+	return err
+}
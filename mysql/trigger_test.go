@@ -0,0 +1,28 @@
+package mysql
+
+import (
+	"testing"
+)
+
+func Test_TriggerDefSQL(t *testing.T) {
+	def := TriggerDef{
+		Name:    "user_audit",
+		Timing:  "after",
+		Event:   TriggerEventUpdate,
+		Body:    "INSERT INTO audit(user_id) VALUES(NEW.id)",
+		Follows: "user_touch",
+	}
+	expect := "CREATE TRIGGER mydb.user_audit AFTER UPDATE ON mydb.user FOR EACH ROW FOLLOWS user_touch INSERT INTO audit(user_id) VALUES(NEW.id)"
+	if s, err := def.sql("mydb.user"); err != nil || s != expect {
+		t.Errorf("expect %q, got %q, %v", expect, s, err)
+	}
+
+	def.Precedes = "user_check"
+	if _, err := def.sql("mydb.user"); err != errTriggerOrderConflict {
+		t.Error(err)
+	}
+	def.Precedes, def.Timing = "", "INSTEAD OF"
+	if _, err := def.sql("mydb.user"); err != errInvalidTriggerTiming {
+		t.Error(err)
+	}
+}
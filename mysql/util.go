@@ -2,8 +2,11 @@ package mysql
 
 import (
 	"database/sql"
+	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // parseTableSchema parse database(equal to schema in mysql) name and table name in param schema.
@@ -113,3 +116,137 @@ func exist(r *sql.Row) (bool, error) {
 		return false, err
 	}
 }
+
+// serverVersion is the version of the mysql server, parsed from VERSION()
+type serverVersion struct {
+	major, minor, patch int
+	mariadb             bool
+}
+
+// getServerVersion gets the version of the connected server
+func getServerVersion(db *sql.DB) (v serverVersion, err error) {
+	var version string
+	if err = db.QueryRow("SELECT VERSION()").Scan(&version); err != nil {
+		return
+	}
+	return parseServerVersion(version), nil
+}
+
+// parseServerVersion parse version such as 5.7.42-log or 8.0.33-0ubuntu0.22.04.2
+func parseServerVersion(version string) (v serverVersion) {
+	v.mariadb = strings.Contains(strings.ToLower(version), "mariadb")
+	if v.mariadb {
+		// the handshake of MariaDB reports 5.5.5-10.x.y for old replication clients
+		version = strings.TrimPrefix(version, "5.5.5-")
+	}
+	if i := strings.IndexAny(version, "-+ "); i >= 0 {
+		version = version[:i]
+	}
+	parts := strings.SplitN(version, ".", 3)
+	nums := []*int{&v.major, &v.minor, &v.patch}
+	for i, p := range parts {
+		*nums[i], _ = strconv.Atoi(p)
+	}
+	return
+}
+
+// atLeast report whether v has the features of MySQL major.minor.patch. The versions of MariaDB are not comparable
+// with those of MySQL: MariaDB has none of the features of 8.0, and from 10.1 on those of 5.7 this package uses.
+func (v serverVersion) atLeast(major, minor, patch int) bool {
+	if v.mariadb {
+		mysql := serverVersion{major: 5, minor: 5}
+		if v.newerThan(10, 1, 0) {
+			mysql = serverVersion{major: 5, minor: 7}
+		}
+		return mysql.newerThan(major, minor, patch)
+	}
+	return v.newerThan(major, minor, patch)
+}
+
+// newerThan report whether v is equal to or newer than major.minor.patch
+func (v serverVersion) newerThan(major, minor, patch int) bool {
+	if v.major != major {
+		return v.major > major
+	}
+	if v.minor != minor {
+		return v.minor > minor
+	}
+	return v.patch >= patch
+}
+
+// quoteTime quote t as a DATETIME literal
+func quoteTime(t time.Time) string {
+	return "'" + t.Format("2006-01-02 15:04:05") + "'"
+}
+
+// scanner is implemented by *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// splitSchema split database.name which is already parsed
+func splitSchema(schema string) (database, name string) {
+	i := strings.Index(schema, ".")
+	return schema[:i], schema[i+1:]
+}
+
+// nullTime scan a nullable DATETIME or TIMESTAMP column into *t whether or not parseTime is set in the dsn.
+// Text values are parsed in UTC like go-sql-driver does, zero dates are scanned as nil.
+type nullTime struct {
+	t **time.Time
+}
+
+// Scan implements sql.Scanner
+func (n nullTime) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+		*n.t = nil
+		return nil
+	case time.Time:
+		if v.IsZero() {
+			*n.t = nil
+		} else {
+			*n.t = &v
+		}
+		return nil
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	default:
+		return fmt.Errorf("unsupported type %T for time", src)
+	}
+	if strings.HasPrefix(s, "0000-00-00") {
+		*n.t = nil
+		return nil
+	}
+	layout := "2006-01-02 15:04:05.999999"
+	if len(s) == len("2006-01-02") {
+		layout = "2006-01-02"
+	}
+	t, err := time.ParseInLocation(layout, s, time.UTC)
+	if err != nil {
+		return err
+	}
+	*n.t = &t
+	return nil
+}
+
+// objectSchema get database.name of an object living in a database such as an event or a trigger,
+// using current database when param database is empty. Return errEmpty if name is empty.
+func objectSchema(db *sql.DB, database, name string, errEmpty error) (string, error) {
+	name = strings.Trim(name, " ")
+	if name == "" {
+		return "", errEmpty
+	}
+	database = strings.Trim(database, " ")
+	if database == "" {
+		var err error
+		database, err = getDatabaseName(db)
+		if err != nil {
+			return "", err
+		}
+	}
+	return database + "." + name, nil
+}
@@ -0,0 +1,46 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_parseServerVersion(t *testing.T) {
+	cases := map[string]serverVersion{
+		"5.7.42-log":                 {5, 7, 42, false},
+		"8.0.33-0ubuntu0.22.04.2":    {8, 0, 33, false},
+		"8.0.16":                     {8, 0, 16, false},
+		"10.6.12-MariaDB-1:10.6.12+": {10, 6, 12, true},
+	}
+	for s, expect := range cases {
+		if v := parseServerVersion(s); v != expect {
+			t.Errorf("%s: expect %v, got %v", s, expect, v)
+		}
+	}
+	v := serverVersion{8, 0, 16, false}
+	if !v.atLeast(8, 0, 16) || !v.atLeast(5, 7, 0) || v.atLeast(8, 0, 17) || v.atLeast(8, 1, 0) {
+		t.Error(v)
+	}
+	v = parseServerVersion("10.6.12-MariaDB-1:10.6.12+")
+	if v.atLeast(8, 0, 0) || !v.atLeast(5, 7, 0) || !v.atLeast(5, 6, 5) {
+		t.Error(v)
+	}
+	v = parseServerVersion("5.5.5-10.0.38-MariaDB")
+	if !v.mariadb || v.major != 10 || v.atLeast(5, 6, 5) || !v.atLeast(5, 5, 0) {
+		t.Error(v)
+	}
+}
+
+func Test_nullTime(t *testing.T) {
+	var at *time.Time
+	for _, src := range []interface{}{[]byte("2018-10-11 12:00:00"), "2018-10-11 12:00:00.5", time.Date(2018, 10, 11, 12, 0, 0, 0, time.UTC)} {
+		if err := (nullTime{&at}).Scan(src); err != nil || at == nil || at.Format("2006-01-02 15") != "2018-10-11 12" {
+			t.Error(src, at, err)
+		}
+	}
+	for _, src := range []interface{}{nil, []byte("0000-00-00 00:00:00")} {
+		if err := (nullTime{&at}).Scan(src); err != nil || at != nil {
+			t.Error(src, at, err)
+		}
+	}
+}
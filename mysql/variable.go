@@ -0,0 +1,194 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// variableName matches the name of system variables
+var variableName = regexp.MustCompile(`^[A-Za-z0-9_.]+$`)
+
+// Variable is a server variable or status, with typed decoding of its value
+type Variable struct {
+	Name  string
+	Value string
+}
+
+// String return the raw value
+func (v Variable) String() string {
+	return v.Value
+}
+
+// Bool decode ON/OFF, YES/NO, TRUE/FALSE and 1/0
+func (v Variable) Bool() (bool, error) {
+	switch strings.ToUpper(strings.TrimSpace(v.Value)) {
+	case "ON", "YES", "TRUE", "1":
+		return true, nil
+	case "OFF", "NO", "FALSE", "0":
+		return false, nil
+	}
+	return false, errInvalidBoolValue
+}
+
+// Int decode a signed integer
+func (v Variable) Int() (int64, error) {
+	return strconv.ParseInt(strings.TrimSpace(v.Value), 10, 64)
+}
+
+// Uint decode an unsigned integer
+func (v Variable) Uint() (uint64, error) {
+	return strconv.ParseUint(strings.TrimSpace(v.Value), 10, 64)
+}
+
+// Float decode a floating point number
+func (v Variable) Float() (float64, error) {
+	return strconv.ParseFloat(strings.TrimSpace(v.Value), 64)
+}
+
+// Size decode a size in bytes, with an optional suffix K, M, G or T in powers of 1024 as in option files.
+func (v Variable) Size() (uint64, error) {
+	s := strings.ToUpper(strings.TrimSpace(v.Value))
+	if s == "" {
+		return 0, errInvalidSizeValue
+	}
+	var shift uint
+	switch s[len(s)-1] {
+	case 'K':
+		shift = 10
+	case 'M':
+		shift = 20
+	case 'G':
+		shift = 30
+	case 'T':
+		shift = 40
+	}
+	if shift > 0 {
+		s = s[:len(s)-1]
+	}
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, errInvalidSizeValue
+	}
+	if n > (1<<64-1)>>shift {
+		return 0, errInvalidSizeValue
+	}
+	return n << shift, nil
+}
+
+// GetVariable get the global value of a system variable
+func GetVariable(db *sql.DB, name string) (Variable, error) {
+	name = strings.Trim(name, " ")
+	if !variableName.MatchString(name) {
+		return Variable{}, errInvalidVariableName
+	}
+	var v Variable
+	err := db.QueryRow("SHOW GLOBAL VARIABLES WHERE Variable_name = ?", name).Scan(&v.Name, &v.Value)
+	if err == sql.ErrNoRows {
+		return Variable{}, errVariableNotExist
+	}
+	return v, err
+}
+
+// SetSessionVariable set a session variable. Sessions are per connection, so it takes a connection pinned
+// by db.Conn on which the following statements run.
+func SetSessionVariable(conn *sql.Conn, name string, value interface{}) error {
+	name = strings.Trim(name, " ")
+	if !variableName.MatchString(name) {
+		return errInvalidVariableName
+	}
+	_, err := conn.ExecContext(context.Background(), "SET SESSION "+name+" = ?", value)
+	return err
+}
+
+// SetGlobalVariable set a global variable. The value is kept after restart with SET PERSIST if persist is true,
+// which requires 8.0 or newer.
+func SetGlobalVariable(db *sql.DB, name string, value interface{}, persist bool) error {
+	name = strings.Trim(name, " ")
+	if !variableName.MatchString(name) {
+		return errInvalidVariableName
+	}
+	scope := "GLOBAL"
+	if persist {
+		v, err := getServerVersion(db)
+		if err != nil {
+			return err
+		}
+		if !v.atLeast(8, 0, 0) {
+			return errUnsupportedServer
+		}
+		scope = "PERSIST"
+	}
+	_, err := db.Exec("SET "+scope+" "+name+" = ?", value)
+	return err
+}
+
+// StatusSnapshot is the result of SHOW GLOBAL STATUS at a moment
+type StatusSnapshot struct {
+	Time   time.Time
+	Values map[string]string
+}
+
+// Get get a status variable, the value is empty if it does not exist
+func (s *StatusSnapshot) Get(name string) Variable {
+	return Variable{Name: name, Value: s.Values[name]}
+}
+
+// GetStatus take a snapshot of SHOW GLOBAL STATUS
+func GetStatus(db *sql.DB) (*StatusSnapshot, error) {
+	rows, err := db.Query("SHOW GLOBAL STATUS")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	s := &StatusSnapshot{Time: time.Now(), Values: make(map[string]string)}
+	for rows.Next() {
+		var name, value string
+		if err = rows.Scan(&name, &value); err != nil {
+			return nil, err
+		}
+		s.Values[name] = value
+	}
+	return s, rows.Err()
+}
+
+// StatusDiff is the change of numeric status variables between two snapshots
+type StatusDiff struct {
+	Interval time.Duration
+	Deltas   map[string]float64
+}
+
+// DiffStatus compute the change from prev to cur. The interval is the difference of Uptime
+// when both snapshots have it, which is more precise than the time the snapshots were taken.
+func DiffStatus(prev, cur *StatusSnapshot) *StatusDiff {
+	d := &StatusDiff{Interval: cur.Time.Sub(prev.Time), Deltas: make(map[string]float64)}
+	prevUptime, err1 := prev.Get("Uptime").Uint()
+	curUptime, err2 := cur.Get("Uptime").Uint()
+	if err1 == nil && err2 == nil && curUptime > prevUptime {
+		d.Interval = time.Duration(curUptime-prevUptime) * time.Second
+	}
+	for name, value := range cur.Values {
+		c, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			continue
+		}
+		p, err := strconv.ParseFloat(prev.Values[name], 64)
+		if err != nil {
+			continue
+		}
+		d.Deltas[name] = c - p
+	}
+	return d
+}
+
+// Rate get the change per second of a status variable such as Questions or Innodb_rows_read
+func (d *StatusDiff) Rate(name string) float64 {
+	if d.Interval <= 0 {
+		return 0
+	}
+	return d.Deltas[name] / d.Interval.Seconds()
+}
@@ -0,0 +1,43 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_Variable(t *testing.T) {
+	if b, err := (Variable{Value: "ON"}).Bool(); err != nil || !b {
+		t.Error(b, err)
+	}
+	if b, err := (Variable{Value: "0"}).Bool(); err != nil || b {
+		t.Error(b, err)
+	}
+	if _, err := (Variable{Value: "DISABLED"}).Bool(); err != errInvalidBoolValue {
+		t.Error(err)
+	}
+	if n, err := (Variable{Value: "-3"}).Int(); err != nil || n != -3 {
+		t.Error(n, err)
+	}
+	sizes := map[string]uint64{"134217728": 134217728, "128M": 128 << 20, "16k": 16 << 10, "1G": 1 << 30}
+	for s, expect := range sizes {
+		if n, err := (Variable{Value: s}).Size(); err != nil || n != expect {
+			t.Error(s, n, err)
+		}
+	}
+	if _, err := (Variable{Value: "M"}).Size(); err != errInvalidSizeValue {
+		t.Error(err)
+	}
+}
+
+func Test_DiffStatus(t *testing.T) {
+	now := time.Now()
+	prev := &StatusSnapshot{Time: now, Values: map[string]string{"Uptime": "100", "Questions": "1000", "Innodb_rows_read": "50", "Ssl_version": ""}}
+	cur := &StatusSnapshot{Time: now.Add(11 * time.Second), Values: map[string]string{"Uptime": "110", "Questions": "1500", "Innodb_rows_read": "250", "Ssl_version": ""}}
+	d := DiffStatus(prev, cur)
+	if d.Interval != 10*time.Second || d.Rate("Questions") != 50 || d.Rate("Innodb_rows_read") != 20 {
+		t.Error(d)
+	}
+	if _, ok := d.Deltas["Ssl_version"]; ok {
+		t.Error(d.Deltas)
+	}
+}
@@ -0,0 +1,210 @@
+package mysql
+
+import (
+	"database/sql"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// viewTableReference matches a table after FROM or JOIN in a view definition, which the server stores
+// with backquoted and schema qualified names such as from (`mydb`.`user` `u` join `mydb`.`role`)
+var viewTableReference = regexp.MustCompile("(?i)\\b(?:from|join)[\\s(]+`((?:[^`]|``)+)`\\.`((?:[^`]|``)+)`")
+
+// View algorithms
+const (
+	ViewAlgorithmUndefined = "UNDEFINED"
+	ViewAlgorithmMerge     = "MERGE"
+	ViewAlgorithmTemptable = "TEMPTABLE"
+)
+
+// View SQL security
+const (
+	ViewSecurityDefiner = "DEFINER"
+	ViewSecurityInvoker = "INVOKER"
+)
+
+// View check options
+const (
+	ViewCheckCascaded = "CASCADED"
+	ViewCheckLocal    = "LOCAL"
+)
+
+// ViewOptions are the optional clauses of CREATE VIEW, empty fields are left to the server.
+type ViewOptions struct {
+	Algorithm   string // ViewAlgorithmUndefined | ViewAlgorithmMerge | ViewAlgorithmTemptable
+	Security    string // ViewSecurityDefiner | ViewSecurityInvoker
+	CheckOption string // ViewCheckCascaded | ViewCheckLocal, WITH CHECK OPTION
+}
+
+// DescView is the detail information of a view
+type DescView struct {
+	Schema      string
+	Name        string
+	Definition  string
+	CheckOption string // NONE | CASCADED | LOCAL
+	Updatable   bool
+	Definer     string
+	Security    string // DEFINER | INVOKER
+	Charset     string // character_set_client when the view was created
+	Collation   string // collation_connection when the view was created
+}
+
+// ViewTable is a table or view referenced by a view
+type ViewTable struct {
+	Schema string
+	Table  string
+}
+
+// ViewExist check whether a view exists
+// Use the currently selected database if schema does not contain database.
+func ViewExist(db *sql.DB, schema string) (bool, error) {
+	database, view, err := parseTableSchema(db, schema)
+	if err != nil {
+		return false, err
+	}
+	r := db.QueryRow(
+		`SELECT TABLE_NAME 
+			FROM information_schema.VIEWS 
+			WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?`, database, view,
+	)
+	return exist(r)
+}
+
+// CreateOrReplaceView create a view of query, or replace it if exists. opts can be nil.
+// Use the currently selected database if schema does not contain database.
+func CreateOrReplaceView(db *sql.DB, schema, query string, opts *ViewOptions) error {
+	database, view, err := parseTableSchema(db, schema)
+	if err != nil {
+		return err
+	}
+	if opts == nil {
+		opts = &ViewOptions{}
+	}
+	var stmt string
+	if stmt, err = opts.sql(database+"."+view, query); err != nil {
+		return err
+	}
+	_, err = db.Exec(stmt)
+	return err
+}
+
+// sql renders CREATE OR REPLACE VIEW schema AS query
+func (o *ViewOptions) sql(schema, query string) (string, error) {
+	query = strings.Trim(query, " ")
+	if query == "" {
+		return "", errEmptyParamQuery
+	}
+	stmt := "CREATE OR REPLACE"
+	if algorithm := strings.Trim(o.Algorithm, " "); algorithm != "" {
+		stmt += " ALGORITHM = " + strings.ToUpper(algorithm)
+	}
+	if security := strings.Trim(o.Security, " "); security != "" {
+		stmt += " SQL SECURITY " + strings.ToUpper(security)
+	}
+	stmt += " VIEW " + schema + " AS " + query
+	if check := strings.Trim(o.CheckOption, " "); check != "" {
+		stmt += " WITH " + strings.ToUpper(check) + " CHECK OPTION"
+	}
+	return stmt, nil
+}
+
+// DropViewIfExist drop a specific view if exists
+// Use the currently selected database if schema does not contain database.
+func DropViewIfExist(db *sql.DB, schema string) error {
+	database, view, err := parseTableSchema(db, schema)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec("DROP VIEW IF EXISTS " + database + "." + view)
+	return err
+}
+
+// DescribeView get the detail information of view
+// Use the currently selected database if schema does not contain database.
+func DescribeView(db *sql.DB, schema string) (*DescView, error) {
+	database, view, err := parseTableSchema(db, schema)
+	if err != nil {
+		return nil, err
+	}
+	dv := &DescView{}
+	var updatable string
+	err = db.QueryRow(
+		`SELECT TABLE_SCHEMA, TABLE_NAME, VIEW_DEFINITION, CHECK_OPTION, IS_UPDATABLE, 
+			DEFINER, SECURITY_TYPE, CHARACTER_SET_CLIENT, COLLATION_CONNECTION 
+			FROM information_schema.VIEWS 
+			WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?`, database, view,
+	).Scan(&dv.Schema, &dv.Name, &dv.Definition, &dv.CheckOption, &updatable,
+		&dv.Definer, &dv.Security, &dv.Charset, &dv.Collation,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errViewNotExist
+		}
+		return nil, err
+	}
+	dv.Updatable = updatable == "YES"
+	return dv, nil
+}
+
+// ViewDependencies get the tables and views referenced by a view from information_schema.VIEW_TABLE_USAGE.
+// Before 8.0.13, which does not have it, they are found in VIEWS.VIEW_DEFINITION instead.
+// Use the currently selected database if schema does not contain database.
+func ViewDependencies(db *sql.DB, schema string) ([]ViewTable, error) {
+	database, view, err := parseTableSchema(db, schema)
+	if err != nil {
+		return nil, err
+	}
+	var v serverVersion
+	v, err = getServerVersion(db)
+	if err != nil {
+		return nil, err
+	}
+	if !v.atLeast(8, 0, 13) {
+		var dv *DescView
+		if dv, err = DescribeView(db, database+"."+view); err != nil {
+			return nil, err
+		}
+		return viewDefinitionTables(dv.Definition), nil
+	}
+	rows, err := db.Query(
+		`SELECT TABLE_SCHEMA, TABLE_NAME 
+			FROM information_schema.VIEW_TABLE_USAGE 
+			WHERE VIEW_SCHEMA = ? AND VIEW_NAME = ? 
+			ORDER BY TABLE_SCHEMA, TABLE_NAME`, database, view,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []ViewTable
+	for rows.Next() {
+		var t ViewTable
+		if err = rows.Scan(&t.Schema, &t.Table); err != nil {
+			return nil, err
+		}
+		tables = append(tables, t)
+	}
+	return tables, rows.Err()
+}
+
+// viewDefinitionTables find the tables after FROM and JOIN in the definition of a view, sorted and without duplicates
+func viewDefinitionTables(definition string) []ViewTable {
+	seen := make(map[ViewTable]bool)
+	var tables []ViewTable
+	for _, m := range viewTableReference.FindAllStringSubmatch(definition, -1) {
+		t := ViewTable{Schema: strings.Replace(m[1], "``", "`", -1), Table: strings.Replace(m[2], "``", "`", -1)}
+		if !seen[t] {
+			seen[t] = true
+			tables = append(tables, t)
+		}
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		if tables[i].Schema != tables[j].Schema {
+			return tables[i].Schema < tables[j].Schema
+		}
+		return tables[i].Table < tables[j].Table
+	})
+	return tables
+}
@@ -0,0 +1,34 @@
+package mysql
+
+import (
+	"testing"
+)
+
+func Test_ViewOptionsSQL(t *testing.T) {
+	opts := &ViewOptions{Algorithm: ViewAlgorithmMerge, Security: "invoker", CheckOption: ViewCheckLocal}
+	expect := "CREATE OR REPLACE ALGORITHM = MERGE SQL SECURITY INVOKER VIEW mydb.active_user AS SELECT * FROM user WHERE active = 1 WITH LOCAL CHECK OPTION"
+	if s, err := opts.sql("mydb.active_user", "SELECT * FROM user WHERE active = 1"); err != nil || s != expect {
+		t.Errorf("expect %q, got %q, %v", expect, s, err)
+	}
+	if s, _ := (&ViewOptions{}).sql("mydb.v", "SELECT 1"); s != "CREATE OR REPLACE VIEW mydb.v AS SELECT 1" {
+		t.Error(s)
+	}
+	if _, err := (&ViewOptions{}).sql("mydb.v", " "); err != errEmptyParamQuery {
+		t.Error(err)
+	}
+}
+
+func Test_viewDefinitionTables(t *testing.T) {
+	definition := "select `u`.`id` AS `id`,`r`.`name` AS `role` from ((`mydb`.`user` `u` join `mydb`.`role` `r` on((`u`.`role_id` = `r`.`id`))) " +
+		"left join `log`.`login` on((`log`.`login`.`user_id` = `u`.`id`))) where `u`.`id` in (select `mydb`.`user`.`id` from `mydb`.`user`)"
+	tables := viewDefinitionTables(definition)
+	expect := []ViewTable{{"log", "login"}, {"mydb", "role"}, {"mydb", "user"}}
+	if len(tables) != len(expect) {
+		t.Fatal(tables)
+	}
+	for i := range expect {
+		if tables[i] != expect[i] {
+			t.Error(tables)
+		}
+	}
+}